@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxAliasDepth bounds how many aliases may expand into one another
+const maxAliasDepth = 16
+
+// aliasConfig is the aliases.json file in the config directory, e.g.
+//
+//	{"aliases": {"rs": "registry servers --status active"}}
+type aliasConfig struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+func aliasesPath() string {
+	return filepath.Join(devgenDir(), "aliases.json")
+}
+
+// loadAliases reads the configured aliases. A missing file means no aliases.
+func loadAliases() (map[string]string, error) {
+	data, err := os.ReadFile(aliasesPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config aliasConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", aliasesPath(), err)
+	}
+	if config.Aliases == nil {
+		config.Aliases = map[string]string{}
+	}
+	return config.Aliases, nil
+}
+
+// isBuiltinCommand reports whether name is a subcommand of root or one of its
+// aliases. Built-in commands always win over a configured alias.
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	// cobra adds these lazily during Execute
+	return name == "help" || name == "completion"
+}
+
+// commandWordIndex returns the index of the first argument that is not a
+// global flag or a global flag's value, or -1 if there is none
+func commandWordIndex(root *cobra.Command, args []string) int {
+	flags := root.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		flag := flags.Lookup(strings.TrimPrefix(arg, "--"))
+		if !strings.HasPrefix(arg, "--") {
+			// -l debug takes the next argument, -ldebug and -qv do not
+			flag = nil
+			if len(arg) == 2 {
+				flag = flags.ShorthandLookup(arg[1:])
+			}
+		}
+		// A flag that takes a value consumes the next argument
+		if flag != nil && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return -1
+}
+
+// expandAliases replaces a configured alias in the command position of args
+// with its expansion. Aliases may refer to other aliases; a cycle is an error.
+// Arguments after the alias are appended to the expansion.
+func expandAliases(root *cobra.Command, args []string, aliases map[string]string) ([]string, error) {
+	var chain []string
+	for {
+		i := commandWordIndex(root, args)
+		if i < 0 || isBuiltinCommand(root, args[i]) {
+			return args, nil
+		}
+		expansion, ok := aliases[args[i]]
+		if !ok {
+			return args, nil
+		}
+
+		for _, name := range chain {
+			if name == args[i] {
+				return nil, usageError("alias %q is recursive: %s", chain[0], strings.Join(append(chain, args[i]), " → "))
+			}
+		}
+		chain = append(chain, args[i])
+		if len(chain) > maxAliasDepth {
+			return nil, usageError("alias %q expands through more than %d aliases", chain[0], maxAliasDepth)
+		}
+
+		words, err := splitAliasArgs(expansion)
+		if err != nil {
+			return nil, usageError("alias %q: %v", args[i], err)
+		}
+		if len(words) == 0 {
+			return nil, usageError("alias %q is empty", args[i])
+		}
+
+		expanded := make([]string, 0, len(args)+len(words))
+		expanded = append(expanded, args[:i]...)
+		expanded = append(expanded, words...)
+		expanded = append(expanded, args[i+1:]...)
+		args = expanded
+	}
+}
+
+// splitAliasArgs splits an alias expansion into arguments the way a shell
+// would for simple quoting: single quotes are literal, double quotes allow
+// backslash escapes, and unquoted whitespace separates words
+func splitAliasArgs(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// preScanConfigDir sets configDir from a --config-dir argument so aliases are
+// read from the right place before cobra has parsed any flags
+func preScanConfigDir(args []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return
+		}
+		if value, ok := strings.CutPrefix(arg, "--config-dir="); ok {
+			configDir = value
+			return
+		}
+		if arg == "--config-dir" && i+1 < len(args) {
+			configDir = args[i+1]
+			return
+		}
+	}
+}
+
+// resolveAliases returns args with any configured alias expanded. A broken
+// aliases file only matters when the command is not a built-in, so it is
+// reported as a warning and the arguments are passed through unchanged.
+func resolveAliases(root *cobra.Command, args []string) ([]string, error) {
+	preScanConfigDir(args)
+	aliases, err := loadAliases()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring aliases: %v\n", err)
+		return args, nil
+	}
+	if len(aliases) == 0 {
+		return args, nil
+	}
+	return expandAliases(root, args, aliases)
+}
+
+// Alias command
+func newAliasCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "List configured command aliases",
+		Long: `List the command aliases defined in aliases.json in the config directory:
+
+  {"aliases": {"rs": "registry servers --status active"}}
+
+"devgen rs" then runs "devgen registry servers --status active", and any
+further arguments are appended. An alias may expand to another alias, but
+not to itself. Built-in commands cannot be overridden by an alias.`,
+		Example: `  devgen alias
+  devgen rs --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases, err := loadAliases()
+			if err != nil {
+				return err
+			}
+			return listAliases(cmd.Root(), aliases, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// listAliases prints each alias and its expansion, flagging aliases that a
+// built-in command shadows
+func listAliases(root *cobra.Command, aliases map[string]string, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(aliasConfig{Aliases: aliases}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	if len(aliases) == 0 {
+		printResult("No aliases configured in %s\n", aliasesPath())
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	printProgress("🔗 Aliases from %s\n\n", aliasesPath())
+	for _, name := range names {
+		line := fmt.Sprintf("%s = %s", name, aliases[name])
+		if isBuiltinCommand(root, name) {
+			line += "  (shadowed by built-in command)"
+		}
+		printResult("%s\n", line)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+)
+
+// Context keys used to carry the authenticated identity into the session
+const (
+	sshAuthMethodKey  = "devgen.auth_method"
+	sshFingerprintKey = "devgen.key_fingerprint"
+)
+
+// auditEvent is a single line in the audit log
+type auditEvent struct {
+	Timestamp      string `json:"timestamp"`
+	Action         string `json:"action"`
+	SessionID      string `json:"session_id,omitempty"`
+	User           string `json:"user,omitempty"`
+	AuthMethod     string `json:"auth_method,omitempty"`
+	KeyFingerprint string `json:"key_fingerprint,omitempty"`
+	RemoteAddr     string `json:"remote_addr,omitempty"`
+	Command        string `json:"command,omitempty"`
+	Server         string `json:"server,omitempty"`
+}
+
+var auditMu sync.Mutex
+
+// auditLogPath returns the audit log location, defaulting to audit.jsonl in devgenDir
+func auditLogPath() string {
+	if sshAuditLog != "" {
+		return sshAuditLog
+	}
+	return filepath.Join(devgenDir(), "audit.jsonl")
+}
+
+// writeAuditEvent appends an event to the audit log and optionally mirrors it to Logfire
+func writeAuditEvent(event auditEvent) error {
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().Format(time.RFC3339)
+	}
+	event.Command = maskSecrets(event.Command)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %v", err)
+	}
+
+	path := auditLogPath()
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %v", err)
+	}
+
+	if sshAuditLogfire {
+		logToLogfire("info", "SSH audit "+event.Action, map[string]interface{}{
+			"event":           "ssh_audit",
+			"action":          event.Action,
+			"session_id":      event.SessionID,
+			"user":            event.User,
+			"auth_method":     event.AuthMethod,
+			"key_fingerprint": event.KeyFingerprint,
+			"remote_addr":     event.RemoteAddr,
+			"command":         event.Command,
+			"server_name":     event.Server,
+		})
+	}
+
+	return nil
+}
+
+// auditSSHSession records an event for the given session, filling in identity details
+func auditSSHSession(sess ssh.Session, action, command, server string) {
+	ctx := sess.Context()
+	authMethod, _ := ctx.Value(sshAuthMethodKey).(string)
+	fingerprint, _ := ctx.Value(sshFingerprintKey).(string)
+
+	event := auditEvent{
+		Action:         action,
+		SessionID:      ctx.SessionID(),
+		User:           sess.User(),
+		AuthMethod:     authMethod,
+		KeyFingerprint: fingerprint,
+		RemoteAddr:     sess.RemoteAddr().String(),
+		Command:        command,
+		Server:         server,
+	}
+	if err := writeAuditEvent(event); err != nil {
+		fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+	}
+}
+
+// auditSSHAuth records an authentication event before a session exists
+func auditSSHAuth(ctx ssh.Context, action string) {
+	method, _ := ctx.Value(sshAuthMethodKey).(string)
+	event := auditEvent{
+		Action:     action,
+		SessionID:  ctx.SessionID(),
+		User:       ctx.User(),
+		AuthMethod: method,
+		RemoteAddr: ctx.RemoteAddr().String(),
+	}
+	if err := writeAuditEvent(event); err != nil {
+		fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+	}
+}
+
+// publicKeyFingerprint formats a key the same way as ssh-keygen -l (SHA256:...)
+func publicKeyFingerprint(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// auditQuery selects events from the audit log
+type auditQuery struct {
+	path    string
+	since   time.Duration
+	actions []string
+	servers []string
+	limit   int
+	asJSON  bool
+}
+
+// auditEventMatchesAction reports whether an event matches one of actions. Command events also
+// match on the command name, so --action toggle finds "toggle <server>" commands.
+func auditEventMatchesAction(event auditEvent, actions []string) bool {
+	if matchesAny(event.Action, actions) {
+		return true
+	}
+	if event.Action == "command" {
+		if fields := strings.Fields(event.Command); len(fields) > 0 {
+			return matchesAny(fields[0], actions)
+		}
+	}
+	return false
+}
+
+// readAuditEvents returns the events in the log that match the query, oldest
+// first. Lines that are not valid JSON are skipped and counted.
+func readAuditEvents(query auditQuery) ([]auditEvent, int, error) {
+	file, err := os.Open(query.path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var cutoff time.Time
+	if query.since > 0 {
+		cutoff = time.Now().Add(-query.since)
+	}
+
+	var events []auditEvent
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event auditEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			skipped++
+			continue
+		}
+		if !cutoff.IsZero() {
+			ts, err := time.Parse(time.RFC3339, event.Timestamp)
+			if err != nil || ts.Before(cutoff) {
+				continue
+			}
+		}
+		if len(query.actions) > 0 && !auditEventMatchesAction(event, query.actions) {
+			continue
+		}
+		if !matchesAny(event.Server, query.servers) {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, skipped, fmt.Errorf("failed to read audit log: %v", err)
+	}
+
+	if query.limit > 0 && len(events) > query.limit {
+		events = events[len(events)-query.limit:]
+	}
+	return events, skipped, nil
+}
+
+// queryAuditLog prints the matching audit events as a table, TSV or JSON
+func queryAuditLog(query auditQuery) error {
+	events, skipped, err := readAuditEvents(query)
+	if os.IsNotExist(err) {
+		if query.asJSON {
+			printResult("[]\n")
+			return nil
+		}
+		printResult("No audit events recorded yet (%s)\n", query.path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		log.Warn("Skipped unreadable audit log lines", "count", skipped, "file", query.path)
+	}
+
+	if query.asJSON {
+		if events == nil {
+			events = []auditEvent{}
+		}
+		data, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	if len(events) == 0 {
+		printResult("No matching audit events in %s\n", query.path)
+		return nil
+	}
+
+	headers := []string{"TIME", "ACTION", "USER", "REMOTE", "SERVER", "COMMAND"}
+	cells := make([][]string, 0, len(events))
+	for _, event := range events {
+		cells = append(cells, []string{event.Timestamp, event.Action, event.User, event.RemoteAddr, event.Server, event.Command})
+	}
+
+	if !isTerminal(os.Stdout) {
+		printResult("%s", renderTSV(headers, cells))
+		return nil
+	}
+
+	printProgress("📜 Audit log %s (%d events)\n\n", query.path, len(events))
+	printResult("%s\n", renderTable(headers, cells, -1))
+	return nil
+}
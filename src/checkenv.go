@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// serverEnvReport lists the environment variables a server declares and which are missing
+type serverEnvReport struct {
+	Server   string   `json:"server"`
+	Status   string   `json:"status"`
+	Required []string `json:"required"`
+	Missing  []string `json:"missing"`
+}
+
+// missingEnvVars returns the declared variables that are unset or empty
+func missingEnvVars(names []string) []string {
+	missing := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if value, ok := os.LookupEnv(name); !ok || value == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// checkRegistryEnv reports the metadata.environment_vars of active servers (or
+// all servers with includeAll) that are missing from the environment. With
+// strict, any missing variable makes it return a validation error.
+func checkRegistryEnv(strict, includeAll, asJSON bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	reports := []serverEnvReport{}
+	missingTotal := 0
+	for _, server := range registry.Servers {
+		if !includeAll && !isActiveStatus(server.Status) {
+			continue
+		}
+		if len(server.Metadata.EnvironmentVars) == 0 {
+			continue
+		}
+		report := serverEnvReport{
+			Server:   server.Name,
+			Status:   server.Status,
+			Required: server.Metadata.EnvironmentVars,
+			Missing:  missingEnvVars(server.Metadata.EnvironmentVars),
+		}
+		missingTotal += len(report.Missing)
+		reports = append(reports, report)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"ok":      missingTotal == 0,
+			"missing": missingTotal,
+			"servers": reports,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+	} else {
+		printProgress("🔑 Checking environment variables for %d servers\n\n", len(reports))
+		for _, report := range reports {
+			if len(report.Missing) == 0 {
+				printResult("✅ %s: %s\n", report.Server, strings.Join(report.Required, ", "))
+				continue
+			}
+			printResult("❌ %s: missing %s\n", report.Server, strings.Join(report.Missing, ", "))
+		}
+		if missingTotal == 0 {
+			printResult("\nAll required environment variables are set\n")
+		} else {
+			printResult("\n%d missing environment variables\n", missingTotal)
+		}
+	}
+
+	if strict && missingTotal > 0 {
+		return validationError("%d required environment variables are missing", missingTotal)
+	}
+	return nil
+}
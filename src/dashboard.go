@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,8 +22,76 @@ type dashboardModel struct {
 	selected     int
 	gridWidth    int
 	gridHeight   int
+
+	// Terminal size from the last tea.WindowSizeMsg; 0 until the first one arrives
+	width  int
+	height int
 	registry     *MCPRegistry
 	dataLoadedAt time.Time
+
+	filterCategory string
+	filterStatus   string
+
+	// Order within each category group: one of serverSortKeys, or "" for registry order
+	sortKey string
+
+	// Category groups that are folded to a single header line
+	collapsed map[string]bool
+
+	// Toggle confirmation: mode is "always", "deactivate" or "never"
+	confirmMode   string
+	pendingToggle *MCPServer
+
+	// Servers marked for a bulk action, by name, and the status a pending
+	// bulk action will set ("" when none is waiting for confirmation)
+	marked      map[string]bool
+	pendingBulk string
+
+	// Preview mode: toggles only change staged, by server name, until 'w'
+	// writes them. confirmQuit is set after 'q' warned about unsaved changes.
+	preview     bool
+	staged      map[string]string
+	confirmQuit bool
+
+	// Read-only mode disables every key that would change the registry
+	readOnly bool
+
+	statusMessage string
+}
+
+// Dashboard keys that change or stage registry changes; disabled in read-only
+// mode. Enter and space also toggle, but still collapse groups when read-only.
+var dashboardMutatingKeys = map[string]bool{
+	"m": true,
+	"a": true,
+	"d": true,
+	"p": true,
+	"w": true,
+}
+
+// dashboardRow is one navigable line: a category header or a server in an expanded group
+type dashboardRow struct {
+	category string
+	server   int // index into servers, -1 for a category header
+}
+
+// dashboardOptions are the startup settings passed from the command line
+type dashboardOptions struct {
+	category       string
+	status         string
+	confirmToggles string
+	rememberState  bool
+	sortKey        string
+	noSave         bool
+	readOnly       bool
+}
+
+// dashboardState is the UI state persisted between runs when --remember-state is set
+type dashboardState struct {
+	FilterCategory string   `json:"filter_category,omitempty"`
+	FilterStatus   string   `json:"filter_status,omitempty"`
+	SortKey        string   `json:"sort,omitempty"`
+	Collapsed      []string `json:"collapsed,omitempty"`
 }
 
 type serversLoadedMsg struct {
@@ -30,6 +101,18 @@ type serversLoadedMsg struct {
 
 type serverToggledMsg struct{}
 
+// serversBulkUpdatedMsg reports the result of applying a status to the marked servers
+type serversBulkUpdatedMsg struct {
+	status  string
+	changed int
+	err     error
+}
+
+type snapshotSavedMsg struct {
+	path string
+	err  error
+}
+
 // Dashboard styles
 var (
 	dashboardTitleStyle = lipgloss.NewStyle().
@@ -55,6 +138,12 @@ var (
 	dashboardStatusStopped = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FF3131")).
 		Bold(true)
+
+	dashboardEmptyStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#00FFFF")).
+		Foreground(lipgloss.Color("#E3E3E3")).
+		Padding(1, 2)
 )
 
 
@@ -68,7 +157,7 @@ func (m dashboardModel) Init() tea.Cmd {
 
 // Debug function to log key events
 func logKeyEvent(msg tea.KeyMsg) {
-	logFile, _ := os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFile, _ := openDebugLog("key_debug.log")
 	defer logFile.Close()
 	fmt.Fprintf(logFile, "Key: Type=%d, Alt=%t, String=%s, Runes=%v\n", 
 		msg.Type, msg.Alt, msg.String(), msg.Runes)
@@ -82,7 +171,7 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		keyStr := msg.String()
 		
 		// Log key events to both file and Logfire
-		logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ := openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "KEY EVENT: Type=%d, String='%s', Runes=%v\n", msg.Type, msg.String(), msg.Runes)
 		fmt.Fprintf(logFile, "KEY STRING: '%s'\n", keyStr)
 		logFile.Close()
@@ -95,14 +184,43 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"selected_index": m.selected,
 		})
 		
+		// A pending confirmation captures the next key
+		if m.pendingToggle != nil && keyStr != "ctrl+c" {
+			server := *m.pendingToggle
+			m.pendingToggle = nil
+			if keyStr == "y" || keyStr == "Y" {
+				return m.applyToggle(server)
+			}
+			m.statusMessage = fmt.Sprintf("Cancelled toggle of %s", server.Name)
+			return m, nil
+		}
+		if m.pendingBulk != "" && keyStr != "ctrl+c" {
+			status := m.pendingBulk
+			m.pendingBulk = ""
+			if keyStr == "y" || keyStr == "Y" {
+				return m.applyBulk(status)
+			}
+			m.statusMessage = "Cancelled bulk update"
+			return m, nil
+		}
+		
+		if keyStr != "q" {
+			m.confirmQuit = false
+		}
+
+		if m.readOnly && dashboardMutatingKeys[keyStr] {
+			m.statusMessage = "Read-only mode: changes are disabled"
+			return m, nil
+		}
+
 		switch keyStr {
 		case "ctrl+c":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "QUIT: Ctrl+C detected\n")
 			logFile.Close()
 			return m, tea.Quit
 		case "enter":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "ENTER: servers=%d, selected=%d\n", len(m.servers), m.selected)
 			logFile.Close()
 			
@@ -111,33 +229,39 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				"selected_index": m.selected,
 			})
 			
-			if len(m.servers) > 0 && m.selected < len(m.servers) {
-				serverName := m.servers[m.selected].Name
-				logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if category, ok := m.selectedHeader(); ok {
+				return m.toggleGroup(category), nil
+			}
+			if server, ok := m.selectedServer(); ok {
+				serverName := server.Name
+				logFile, _ := openDebugLog("dashboard_debug.log")
 				fmt.Fprintf(logFile, "TOGGLE: Calling toggleServerCmd for %s\n", serverName)
 				logFile.Close()
 				
 				logToLogfire("info", "Toggling server status", map[string]interface{}{
 					"server_name": serverName,
-					"current_status": m.servers[m.selected].Status,
+					"current_status": server.Status,
 				})
 				
-				return m, m.toggleServerCmd(serverName)
+				return m.requestToggle(server)
 			}
 			return m, nil
 		case " ":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "SPACE: servers=%d, selected=%d\n", len(m.servers), m.selected)
 			logFile.Close()
-			if len(m.servers) > 0 && m.selected < len(m.servers) {
-				logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-				fmt.Fprintf(logFile, "TOGGLE: Calling toggleServerCmd for %s\n", m.servers[m.selected].Name)
+			if category, ok := m.selectedHeader(); ok {
+				return m.toggleGroup(category), nil
+			}
+			if server, ok := m.selectedServer(); ok {
+				logFile, _ := openDebugLog("dashboard_debug.log")
+				fmt.Fprintf(logFile, "TOGGLE: Calling toggleServerCmd for %s\n", server.Name)
 				logFile.Close()
-				return m, m.toggleServerCmd(m.servers[m.selected].Name)
+				return m.requestToggle(server)
 			}
 			return m, nil
 		case "up", "k":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "UP: selected %d -> %d\n", m.selected, m.selected-1)
 			logFile.Close()
 			if m.selected > 0 {
@@ -145,15 +269,15 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "down", "j":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "DOWN: selected %d -> %d\n", m.selected, m.selected+1)
 			logFile.Close()
-			if m.selected < len(m.servers)-1 {
+			if m.selected < len(m.rows())-1 {
 				m.selected++
 			}
 			return m, nil
 		case "left", "h":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "LEFT: selected %d -> %d\n", m.selected, m.selected-1)
 			logFile.Close()
 			if m.selected > 0 {
@@ -161,40 +285,126 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "right", "l":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "RIGHT: selected %d -> %d\n", m.selected, m.selected+1)
 			logFile.Close()
-			if m.selected < len(m.servers)-1 {
+			if m.selected < len(m.rows())-1 {
 				m.selected++
 			}
 			return m, nil
 		case "q":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "QUIT: q key detected\n")
 			logFile.Close()
+			if len(m.staged) > 0 && !m.confirmQuit {
+				m.confirmQuit = true
+				m.statusMessage = fmt.Sprintf("%d unsaved changes: press 'q' again to discard them or 'w' to save", len(m.staged))
+				return m, nil
+			}
 			return m, tea.Quit
 		case "r":
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "REFRESH: r key detected, setting loading=true\n")
 			logFile.Close()
 			
 			// Create new model with loading state and return it with the command
 			newModel := m
 			newModel.loading = true
+			invalidateRegistryCache()
 			return newModel, newModel.loadServers()
+		case "c":
+			// Collapse or expand the group under the cursor
+			if rows := m.rows(); m.selected < len(rows) {
+				return m.toggleGroup(rows[m.selected].category), nil
+			}
+			return m, nil
+		case "s", "S":
+			// Snapshot exactly what is on screen: 's' for markdown, 'S' for JSON
+			format := "markdown"
+			if keyStr == "S" {
+				format = "json"
+			}
+			return m, saveSnapshotCmd(m.servers, format)
+		case "m":
+			// Mark or unmark the server under the cursor for a bulk action
+			if server, ok := m.selectedServer(); ok {
+				m = m.toggleMark(server.Name)
+			}
+			return m, nil
+		case "a", "d":
+			// Apply activate/deactivate to every marked server in one save
+			if len(m.marked) == 0 {
+				m.statusMessage = "No servers marked; press 'm' to mark servers"
+				return m, nil
+			}
+			status := "active"
+			if keyStr == "d" {
+				status = "inactive"
+			}
+			return m.requestBulk(status)
+		case "u":
+			// Clear all marks
+			m.marked = map[string]bool{}
+			return m, nil
+		case "x":
+			// Clear startup filters and show every server
+			if m.filterCategory == "" && m.filterStatus == "" {
+				return m, nil
+			}
+			m.filterCategory = ""
+			m.filterStatus = ""
+			return m, m.loadServers()
+		case "e":
+			// Open the server's URL in the browser or its script in $EDITOR
+			if server, ok := m.selectedServer(); ok {
+				return m, openServerCmd(server)
+			}
+			return m, nil
+		case "p":
+			// Switch preview mode; staged changes must be saved or discarded first
+			if m.preview && len(m.staged) > 0 {
+				m.statusMessage = "Save ('w') or discard ('z') staged changes before leaving preview mode"
+				return m, nil
+			}
+			m.preview = !m.preview
+			m.statusMessage = "Preview mode off: toggles are saved immediately"
+			if m.preview {
+				m.statusMessage = "Preview mode on: toggles are staged until you press 'w'"
+			}
+			return m, nil
+		case "w":
+			// Write staged preview changes
+			if len(m.staged) == 0 {
+				m.statusMessage = "No unsaved changes"
+				return m, nil
+			}
+			return m, commitStagedCmd(m.staged)
+		case "z":
+			// Discard staged preview changes
+			if len(m.staged) == 0 {
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Discarded %d unsaved changes", len(m.staged))
+			m.staged = map[string]string{}
+			m.servers = m.visibleServers()
+			return m, nil
+		case "o":
+			// Cycle the order within groups; reload so registry order can be restored
+			m.sortKey = nextSortKey(m.sortKey)
+			return m, m.loadServers()
 		}
 		return m, nil
 		
 	case serversLoadedMsg:
 		// Log UI state update
-		logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ := openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "MSG: serversLoadedMsg received\n")
 		
 		m.loading = false
 		m.registry = msg.registry
 		m.dataLoadedAt = msg.loadedAt
 		if msg.registry != nil {
-			m.servers = msg.registry.Servers
+			m.servers = m.visibleServers()
 			fmt.Fprintf(logFile, "UI UPDATE: Set %d servers in model\n", len(m.servers))
 			
 			// Log the crawl4ai-mcp server status in the UI model
@@ -213,20 +423,58 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.servers = []MCPServer{}
 			fmt.Fprintf(logFile, "UI UPDATE: Set empty servers array\n")
 		}
-		if m.selected >= len(m.servers) {
-			m.selected = len(m.servers) - 1
+		if m.selected >= len(m.rows()) {
+			m.selected = len(m.rows()) - 1
 		}
 		if m.selected < 0 {
 			m.selected = 0
 		}
 		logFile.Close()
 		return m, nil
+	case snapshotSavedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("❌ Snapshot failed: %v", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("✅ Snapshot written to %s", msg.path)
+		}
+		return m, nil
+	case serverOpenedMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMessage = fmt.Sprintf("❌ Open failed: %v", msg.err)
+		case msg.printed:
+			m.statusMessage = fmt.Sprintf("🔗 %s", msg.target)
+		default:
+			m.statusMessage = fmt.Sprintf("✅ Opened %s", msg.target)
+		}
+		return m, nil
+	case stagedCommittedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("❌ Save failed, changes are still staged: %v", msg.err)
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("✅ Saved %d changes", msg.changed)
+		m.staged = map[string]string{}
+		return m, m.loadServers()
+	case serversBulkUpdatedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("❌ Bulk update failed: %v", msg.err)
+			return m, m.loadServers()
+		}
+		m.statusMessage = fmt.Sprintf("✅ Set %d servers to %s", msg.changed, msg.status)
+		m.marked = map[string]bool{}
+		return m, m.loadServers()
 	case serverToggledMsg:
 		// Log that we received the toggle message
-		logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ := openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "MSG: Received serverToggledMsg, triggering reload\n")
 		logFile.Close()
 		return m, m.loadServers()
+	case tea.WindowSizeMsg:
+		// Re-layout on resize: descriptions re-wrap and the list scrolls to fit
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -242,8 +490,54 @@ func (m dashboardModel) View() string {
 		return fmt.Sprintf("\n%s Loading servers...\n", m.spinner.View())
 	}
 
-	header := dashboardTitleStyle.Render("🔌 MCP Server Dashboard")
-	footer := dashboardItemStyle.Render("Press 'enter/space' to toggle, 'q' to quit, arrow keys/hjkl to navigate")
+	title := "🔌 MCP Server Dashboard"
+	if len(m.staged) > 0 {
+		title += fmt.Sprintf(" (preview, %d unsaved)", len(m.staged))
+	} else if m.preview {
+		title += " (preview)"
+	}
+	if m.readOnly {
+		title += " (read-only)"
+	}
+	header := dashboardTitleStyle.Render(title)
+	footerText := "Press 'enter/space' to toggle, 'm' to mark, 'c' to collapse group, 'o' to change sort, 'e' to open server, 'p' for preview mode, 's/S' to snapshot (md/json), 'q' to quit, arrow keys/hjkl to navigate"
+	if m.readOnly {
+		footerText = "Read-only mode: press 'c' to collapse group, 'o' to change sort, 'e' to open server, 's/S' to snapshot (md/json), 'q' to quit, arrow keys/hjkl to navigate"
+	}
+	if m.filterDescription() != "" {
+		footerText += ", 'x' to clear filter"
+	}
+	footer := dashboardItemStyle.Render(footerText)
+	if m.width > 0 {
+		footer = dashboardItemStyle.Width(m.width).Render(footerText)
+	}
+	if m.preview {
+		notice := "✎ Preview mode: toggles are not saved ('p' to leave)"
+		if len(m.staged) > 0 {
+			notice = fmt.Sprintf("✎ Preview mode: %d unsaved changes, 'w' to save, 'z' to discard", len(m.staged))
+		}
+		footer = dashboardSelectedStyle.Render(notice) + "\n" + footer
+	}
+	if len(m.marked) > 0 {
+		footer = dashboardSelectedStyle.Render(fmt.Sprintf("%d selected: 'a' activate, 'd' deactivate, 'u' clear marks", len(m.marked))) + "\n" + footer
+	}
+	if m.pendingToggle != nil {
+		action := "Activate"
+		if isActiveStatus(m.pendingToggle.Status) {
+			action = "Deactivate"
+		}
+		footer = dashboardSelectedStyle.Render(fmt.Sprintf("%s %s? (y/n)", action, m.pendingToggle.Name))
+	}
+	if m.pendingBulk != "" {
+		action := "Activate"
+		if m.pendingBulk == "inactive" {
+			action = "Deactivate"
+		}
+		footer = dashboardSelectedStyle.Render(fmt.Sprintf("%s %d selected servers? (y/n)", action, len(m.marked)))
+	}
+	if m.statusMessage != "" {
+		footer = dashboardHeaderStyle.Render(m.statusMessage) + "\n" + footer
+	}
 
 	// Debug info with timestamp
 	dataLoadedTime := "never"
@@ -251,33 +545,97 @@ func (m dashboardModel) View() string {
 		dataLoadedTime = m.dataLoadedAt.Format("15:04:05")
 	}
 	debugInfo := fmt.Sprintf("Servers: %d | Data loaded at: %s", len(m.servers), dataLoadedTime)
+	if filter := m.filterDescription(); filter != "" {
+		debugInfo += " | Filter: " + filter
+	}
+	if m.sortKey != "" {
+		debugInfo += " | Sort: " + m.sortKey
+	}
 	if len(m.servers) > 0 {
 		selectedServer := "none"
-		if m.selected < len(m.servers) {
-			selectedServer = m.servers[m.selected].Name
+		if server, ok := m.selectedServer(); ok {
+			selectedServer = server.Name
+		} else if category, ok := m.selectedHeader(); ok {
+			selectedServer = "group " + category
 		}
 		debugInfo += fmt.Sprintf(" | Selected: %d (%s)", m.selected, selectedServer)
 	}
 
-	// Simple list - show ALL servers without pagination
+	// Grouped list - category headers followed by the servers of expanded groups
+	rows := m.rows()
+	rendered := make([]string, len(rows))
+	for i, row := range rows {
+		if row.server < 0 {
+			rendered[i] = m.renderGroupHeader(row.category, i == m.selected)
+		} else {
+			server := m.servers[row.server]
+			rendered[i] = m.renderServerCard(server, i == m.selected, m.marked[server.Name])
+		}
+	}
+
+	summary := dashboardHeaderStyle.Render(renderCategorySummary(m.servers))
+	if m.width > 0 {
+		summary = dashboardHeaderStyle.Width(m.width).Render(renderCategorySummary(m.servers))
+		debugInfo = lipgloss.NewStyle().MaxWidth(m.width).Render(debugInfo)
+	}
+
+	// Show only the rows that fit between the header and footer, keeping the cursor visible
+	first, last := 0, len(rows)
+	if m.height > 0 {
+		chrome := lipgloss.Height(header) + lipgloss.Height(summary) + lipgloss.Height(debugInfo) + lipgloss.Height(footer) + 2
+		first, last = visibleRows(rendered, m.selected, m.height-chrome)
+	}
+
 	var serverList strings.Builder
 	renderedCount := 0
-	
-	for i, server := range m.servers {
-		serverLine := m.renderServerCard(server, i == m.selected)
-		serverList.WriteString(serverLine)
-		renderedCount++
+	for i := first; i < last; i++ {
+		serverList.WriteString(rendered[i])
+		if rows[i].server >= 0 {
+			renderedCount++
+		}
 		
-		// Add single line spacing between servers
-		if i < len(m.servers)-1 {
+		// Add single line spacing between rows
+		if i < last-1 {
 			serverList.WriteString("\n")
 		}
 	}
 	debugInfo += fmt.Sprintf(" | Rendered: %d", renderedCount)
 
-	return fmt.Sprintf("%s\n%s\n\n%s\n\n%s", header, debugInfo, serverList.String(), footer)
+	if len(m.servers) == 0 {
+		serverList.WriteString(m.renderEmptyState())
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s\n\n%s\n\n%s", header, summary, debugInfo, serverList.String(), footer)
 }
 
+// renderEmptyState explains why there is nothing to show instead of leaving a blank list
+func (m dashboardModel) renderEmptyState() string {
+	var lines []string
+	switch {
+	case m.registry != nil && m.registry.Version == "ERROR":
+		lines = []string{
+			dashboardStatusStopped.Render("Could not load the registry"),
+			m.registry.Timestamp,
+			"",
+			"Check the --config path, then press 'r' to reload.",
+		}
+	case m.filterDescription() != "":
+		lines = []string{
+			dashboardHeaderStyle.Render("No servers match the current filter"),
+			"Filter: " + m.filterDescription(),
+			"",
+			"Press 'x' to clear the filter.",
+		}
+	default:
+		lines = []string{
+			dashboardHeaderStyle.Render("No MCP servers registered"),
+			fmt.Sprintf("The %s (%s).", emptyRegistryMessage, configFile),
+			"",
+			"Add servers with: devgen registry import <file>",
+		}
+	}
+	return dashboardEmptyStyle.Render(strings.Join(lines, "\n"))
+}
 
 // Load servers from registry
 func (m dashboardModel) loadServers() tea.Cmd {
@@ -285,13 +643,13 @@ func (m dashboardModel) loadServers() tea.Cmd {
 		loadTime := time.Now()
 		
 		// Log reload attempt
-		logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ := openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "LOAD: Starting loadServers from configFile=%s\n", configFile)
 		logFile.Close()
 		
 		registry, err := loadMCPRegistry()
 		if err != nil {
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "LOAD ERROR: %v\n", err)
 			logFile.Close()
 			
@@ -304,7 +662,7 @@ func (m dashboardModel) loadServers() tea.Cmd {
 			return serversLoadedMsg{registry: emptyRegistry, loadedAt: loadTime}
 		}
 
-		logFile, _ = os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ = openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "LOAD: Registry loaded successfully, %d servers\n", len(registry.Servers))
 		logFile.Close()
 
@@ -314,7 +672,7 @@ func (m dashboardModel) loadServers() tea.Cmd {
 		// without overriding the display status.
 
 		// Log loaded servers for debugging
-		logFile, _ = os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ = openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "LOAD: Returning serversLoadedMsg with %d servers\n", len(registry.Servers))
 		
 		// Find and log crawl4ai-mcp specifically
@@ -334,34 +692,36 @@ func (m dashboardModel) loadServers() tea.Cmd {
 func (m dashboardModel) toggleServerCmd(serverName string) tea.Cmd {
 	return func() tea.Msg {
 		// Log toggle attempt
-		logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ := openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "TOGGLE CMD: Starting toggle for server '%s' using configFile=%s\n", serverName, configFile)
 		logFile.Close()
 		
-		// Load registry fresh (same as CLI)
-		registry, err := loadMCPRegistry()
+		// Load registry fresh so edits made outside the dashboard are not overwritten
+		registry, err := reloadRegistry()
 		if err != nil {
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "TOGGLE CMD ERROR: Failed to load registry: %v\n", err)
 			logFile.Close()
 			return serverToggledMsg{} // Still trigger reload even on error
 		}
 
-		logFile, _ = os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		logFile, _ = openDebugLog("dashboard_debug.log")
 		fmt.Fprintf(logFile, "TOGGLE CMD: Registry loaded, %d servers\n", len(registry.Servers))
 		logFile.Close()
 
 		// Toggle the server status (same logic as toggleServer function)
 		found := false
+		var oldStatus, newStatus string
 		for i := range registry.Servers {
 			if registry.Servers[i].Name == serverName {
-				oldStatus := registry.Servers[i].Status
+				oldStatus = registry.Servers[i].Status
 				if registry.Servers[i].Status == "active" || registry.Servers[i].Status == "production-ready" || registry.Servers[i].Status == "running" {
 					registry.Servers[i].Status = "inactive"
 				} else {
 					registry.Servers[i].Status = "active"
 				}
-				logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+				logFile, _ := openDebugLog("dashboard_debug.log")
+				newStatus = registry.Servers[i].Status
 				fmt.Fprintf(logFile, "TOGGLE CMD: Changed %s status from '%s' to '%s'\n", serverName, oldStatus, registry.Servers[i].Status)
 				logFile.Close()
 				found = true
@@ -370,7 +730,7 @@ func (m dashboardModel) toggleServerCmd(serverName string) tea.Cmd {
 		}
 
 		if !found {
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "TOGGLE CMD ERROR: Server '%s' not found\n", serverName)
 			logFile.Close()
 		}
@@ -378,13 +738,16 @@ func (m dashboardModel) toggleServerCmd(serverName string) tea.Cmd {
 		// Save registry fresh (same as CLI)
 		err = saveMCPRegistry(registry)
 		if err != nil {
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "TOGGLE CMD ERROR: Failed to save registry: %v\n", err)
 			logFile.Close()
 		} else {
-			logFile, _ := os.OpenFile("dashboard_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			logFile, _ := openDebugLog("dashboard_debug.log")
 			fmt.Fprintf(logFile, "TOGGLE CMD: Registry saved successfully\n")
 			logFile.Close()
+			if found {
+				logRegistryEvent("toggle", serverName, oldStatus, newStatus)
+			}
 		}
 		
 		// Add small delay to ensure file write completes before triggering reload
@@ -394,8 +757,235 @@ func (m dashboardModel) toggleServerCmd(serverName string) tea.Cmd {
 	}
 }
 
+// filterServers returns the servers matching the category and status filters (empty matches all)
+func filterServers(servers []MCPServer, category, status string) []MCPServer {
+	filtered := []MCPServer{}
+	for _, server := range servers {
+		if category != "" && !strings.EqualFold(serverCategory(server), category) {
+			continue
+		}
+		if status != "" && !matchesStatus(server.Status, []string{status}) {
+			continue
+		}
+		filtered = append(filtered, server)
+	}
+	return filtered
+}
+
+// requestToggle toggles a server, asking for confirmation first when the confirm mode requires it
+func (m dashboardModel) requestToggle(server MCPServer) (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		m.statusMessage = fmt.Sprintf("Read-only mode: %s was not toggled", server.Name)
+		return m, nil
+	}
+
+	needsConfirm := false
+	switch m.confirmMode {
+	case "always":
+		needsConfirm = true
+	case "never":
+		needsConfirm = false
+	default:
+		// Deactivating is the destructive direction, so confirm it by default
+		needsConfirm = isActiveStatus(server.Status)
+	}
+
+	if needsConfirm {
+		m.pendingToggle = &server
+		return m, nil
+	}
+	return m.applyToggle(server)
+}
+
+// toggleMark marks or unmarks a server for a bulk action
+func (m dashboardModel) toggleMark(name string) dashboardModel {
+	marked := make(map[string]bool, len(m.marked)+1)
+	for k, v := range m.marked {
+		marked[k] = v
+	}
+	if marked[name] {
+		delete(marked, name)
+	} else {
+		marked[name] = true
+	}
+	m.marked = marked
+	return m
+}
+
+// markedNames returns the marked server names in a stable order
+func (m dashboardModel) markedNames() []string {
+	names := make([]string, 0, len(m.marked))
+	for name := range m.marked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requestBulk applies status to the marked servers, confirming first when the
+// confirm mode requires it for a single toggle in the same direction
+func (m dashboardModel) requestBulk(status string) (tea.Model, tea.Cmd) {
+	needsConfirm := false
+	switch m.confirmMode {
+	case "always":
+		needsConfirm = true
+	case "never":
+		needsConfirm = false
+	default:
+		needsConfirm = status == "inactive"
+	}
+
+	if needsConfirm {
+		m.pendingBulk = status
+		return m, nil
+	}
+	return m.applyBulk(status)
+}
+
+// bulkSetStatusCmd sets status on every named server with a single registry save
+func bulkSetStatusCmd(names []string, status string) tea.Cmd {
+	return func() tea.Msg {
+		registry, err := reloadRegistry()
+		if err != nil {
+			return serversBulkUpdatedMsg{status: status, err: err}
+		}
+
+		wanted := make(map[string]bool, len(names))
+		for _, name := range names {
+			wanted[name] = true
+		}
+
+		type change struct{ name, oldStatus string }
+		var changes []change
+		for i := range registry.Servers {
+			server := &registry.Servers[i]
+			if !wanted[server.Name] || server.Status == status {
+				continue
+			}
+			changes = append(changes, change{server.Name, server.Status})
+			server.Status = status
+		}
+
+		if len(changes) > 0 {
+			if err := saveMCPRegistry(registry); err != nil {
+				return serversBulkUpdatedMsg{status: status, err: err}
+			}
+			for _, c := range changes {
+				logRegistryEvent("toggle", c.name, c.oldStatus, status)
+			}
+		}
+		return serversBulkUpdatedMsg{status: status, changed: len(changes)}
+	}
+}
+
+// serverCategory returns the grouping key for a server
+func serverCategory(server MCPServer) string {
+	if server.Metadata.Category == "" {
+		return "uncategorized"
+	}
+	return server.Metadata.Category
+}
+
+// groupServers orders servers by category so each group is contiguous. Within a
+// group servers are ordered by sortKey, or keep their original order when it is empty.
+func groupServers(servers []MCPServer, sortKey string) []MCPServer {
+	sort.SliceStable(servers, func(i, j int) bool {
+		ci, cj := serverCategory(servers[i]), serverCategory(servers[j])
+		if ci != cj || sortKey == "" {
+			return ci < cj
+		}
+		return compareServers(sortKey, mcpServerSortFields(servers[i]), mcpServerSortFields(servers[j])) < 0
+	})
+	return servers
+}
+
+// rows flattens the grouped server list into navigable lines, skipping collapsed groups
+func (m dashboardModel) rows() []dashboardRow {
+	var rows []dashboardRow
+	current := ""
+	for i, server := range m.servers {
+		category := serverCategory(server)
+		if i == 0 || category != current {
+			rows = append(rows, dashboardRow{category: category, server: -1})
+			current = category
+		}
+		if !m.collapsed[category] {
+			rows = append(rows, dashboardRow{category: category, server: i})
+		}
+	}
+	return rows
+}
+
+// selectedServer returns the server under the cursor, if the cursor is on a server row
+func (m dashboardModel) selectedServer() (MCPServer, bool) {
+	rows := m.rows()
+	if m.selected < 0 || m.selected >= len(rows) || rows[m.selected].server < 0 {
+		return MCPServer{}, false
+	}
+	return m.servers[rows[m.selected].server], true
+}
+
+// selectedHeader returns the category when the cursor is on a group header
+func (m dashboardModel) selectedHeader() (string, bool) {
+	rows := m.rows()
+	if m.selected < 0 || m.selected >= len(rows) || rows[m.selected].server >= 0 {
+		return "", false
+	}
+	return rows[m.selected].category, true
+}
+
+// toggleGroup collapses or expands a category and keeps the cursor on its header
+func (m dashboardModel) toggleGroup(category string) dashboardModel {
+	collapsed := make(map[string]bool, len(m.collapsed)+1)
+	for k, v := range m.collapsed {
+		collapsed[k] = v
+	}
+	collapsed[category] = !collapsed[category]
+	m.collapsed = collapsed
+
+	for i, row := range m.rows() {
+		if row.server < 0 && row.category == category {
+			m.selected = i
+			break
+		}
+	}
+	return m
+}
+
+// renderGroupHeader renders a category header line with its fold marker
+func (m dashboardModel) renderGroupHeader(category string, selected bool) string {
+	count := 0
+	for _, server := range m.servers {
+		if serverCategory(server) == category {
+			count++
+		}
+	}
+
+	marker := "▾"
+	if m.collapsed[category] {
+		marker = "▸"
+	}
+	line := fmt.Sprintf("%s %s %s (%d)", marker, categoryIcon(category), category, count)
+	if selected {
+		return "▶ " + dashboardSelectedStyle.Render(line)
+	}
+	return "  " + dashboardHeaderStyle.Render(line)
+}
+
+// filterDescription summarizes the active filters for the header
+func (m dashboardModel) filterDescription() string {
+	var parts []string
+	if m.filterCategory != "" {
+		parts = append(parts, "category="+m.filterCategory)
+	}
+	if m.filterStatus != "" {
+		parts = append(parts, "status="+m.filterStatus)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // Create and run the dashboard
-func runDashboard() error {
+func runDashboard(opts dashboardOptions) error {
 	// Log dashboard startup to Logfire
 	logToLogfire("info", "Dashboard starting up", map[string]interface{}{
 		"config_file": configFile,
@@ -417,11 +1007,44 @@ func runDashboard() error {
 		gridHeight:   13,
 		registry:     nil,
 		dataLoadedAt: time.Time{},
+
+		filterCategory: opts.category,
+		filterStatus:   opts.status,
+		collapsed:      map[string]bool{},
+		confirmMode:    opts.confirmToggles,
+		marked:         map[string]bool{},
+		sortKey:        opts.sortKey,
+		preview:        opts.noSave,
+		staged:         map[string]string{},
+		readOnly:       opts.readOnly,
+	}
+
+	// Restore the previous view; explicit command line filters take precedence
+	if opts.rememberState {
+		state := loadDashboardState()
+		if opts.category == "" && opts.status == "" {
+			m.filterCategory = state.FilterCategory
+			m.filterStatus = state.FilterStatus
+		}
+		for _, category := range state.Collapsed {
+			m.collapsed[category] = true
+		}
+		if opts.sortKey == "" && validateSortKey(state.SortKey) == nil {
+			m.sortKey = state.SortKey
+		}
 	}
 
 	// Run the dashboard with Ghostty terminal optimizations
 	p := tea.NewProgram(m, tea.WithAltScreen())
-	_, err := p.Run()
+	final, err := p.Run()
+	
+	if opts.rememberState && err == nil {
+		if fm, ok := final.(dashboardModel); ok {
+			if saveErr := saveDashboardState(fm.state()); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to save dashboard state: %v\n", saveErr)
+			}
+		}
+	}
 	
 	// Log dashboard shutdown
 	logToLogfire("info", "Dashboard shutting down", map[string]interface{}{
@@ -430,8 +1053,101 @@ func runDashboard() error {
 	
 	return err
 }
+
+// dashboardStatePath returns where the dashboard UI state is stored
+func dashboardStatePath() string {
+	return filepath.Join(devgenDir(), "dashboard_state.json")
+}
+
+// loadDashboardState reads the saved UI state, ignoring a missing or corrupt file
+func loadDashboardState() dashboardState {
+	var state dashboardState
+	data, err := os.ReadFile(dashboardStatePath())
+	if err != nil {
+		return dashboardState{}
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return dashboardState{}
+	}
+	return state
+}
+
+func saveDashboardState(state dashboardState) error {
+	path := dashboardStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// state captures the parts of the model that are persisted between runs
+func (m dashboardModel) state() dashboardState {
+	state := dashboardState{
+		FilterCategory: m.filterCategory,
+		FilterStatus:   m.filterStatus,
+		SortKey:        m.sortKey,
+	}
+	for category, collapsed := range m.collapsed {
+		if collapsed {
+			state.Collapsed = append(state.Collapsed, category)
+		}
+	}
+	sort.Strings(state.Collapsed)
+	return state
+}
+
+// Category icon mapping
+var dashboardCategoryIcons = map[string]string{
+	"knowledge":      "🧠",
+	"development":    "⚡",
+	"web":            "🌐",
+	"framework":      "🔧",
+	"database":       "💾",
+	"infrastructure": "🏗️",
+}
+
+// categoryIcon returns the emoji for a category, with a default marker for unmapped ones
+func categoryIcon(category string) string {
+	if icon := dashboardCategoryIcons[category]; icon != "" {
+		return icon
+	}
+	return "📦"
+}
+
+// renderCategorySummary renders per-category active/total counts for the header band
+func renderCategorySummary(servers []MCPServer) string {
+	type counts struct{ active, total int }
+	byCategory := make(map[string]*counts)
+	var categories []string
+	for _, server := range servers {
+		category := serverCategory(server)
+		c, ok := byCategory[category]
+		if !ok {
+			c = &counts{}
+			byCategory[category] = c
+			categories = append(categories, category)
+		}
+		c.total++
+		if isActiveStatus(server.Status) {
+			c.active++
+		}
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, 0, len(categories))
+	for _, category := range categories {
+		c := byCategory[category]
+		parts = append(parts, fmt.Sprintf("%s %s: %d active / %d", categoryIcon(category), category, c.active, c.total))
+	}
+	return strings.Join(parts, "  ")
+}
+
 // Render a single server card with proper text wrapping
-func (m dashboardModel) renderServerCard(server MCPServer, selected bool) string {
+func (m dashboardModel) renderServerCard(server MCPServer, selected, marked bool) string {
 	// Determine status color
 	var statusStyle lipgloss.Style
 	if server.Status == "active" || server.Status == "production-ready" || server.Status == "running" {
@@ -447,24 +1163,20 @@ func (m dashboardModel) renderServerCard(server MCPServer, selected bool) string
 	}
 	
 	// Wrap description to terminal width
-	description := wrapText(server.Description, 80)
-	
-	// Category icon mapping
-	categoryIcon := map[string]string{
-		"knowledge":       "🧠",
-		"development":     "⚡",
-		"web":            "🌐",
-		"framework":      "🔧",
-		"database":       "💾",
-		"infrastructure": "🏗️",
-	}
+	description := wrapText(server.Description, m.descriptionWidth())
 	
-	icon := categoryIcon[server.Metadata.Category]
-	if icon == "" {
-		icon = "📦"
-	}
+	icon := categoryIcon(server.Metadata.Category)
 	
 	// Build simple one-line format with wrapped description
+	// Checkboxes only appear once something is marked, so the normal layout is unchanged
+	if len(m.marked) > 0 {
+		checkbox := "[ ]"
+		if marked {
+			checkbox = dashboardSelectedStyle.Render("[x]")
+		}
+		icon = checkbox + " " + icon
+	}
+
 	line1 := fmt.Sprintf("%s %s [%s • %d tools]", 
 		icon,
 		nameStyle.Render(server.Name),
@@ -480,6 +1192,44 @@ func (m dashboardModel) renderServerCard(server MCPServer, selected bool) string
 	return fmt.Sprintf("  %s\n%s", line1, line2)
 }
 
+// descriptionWidth is the wrap width for server descriptions: the terminal
+// width less the card indent, or 80 before the size is known
+func (m dashboardModel) descriptionWidth() int {
+	if m.width == 0 {
+		return 80
+	}
+	if width := m.width - 4; width > 20 {
+		return width
+	}
+	return 20
+}
+
+// visibleRows returns the [first, last) range of rendered rows that fits in
+// height lines while keeping selected on screen. Rows scroll only once the
+// cursor would otherwise move past the bottom.
+func visibleRows(rendered []string, selected, height int) (int, int) {
+	if height < 1 {
+		height = 1
+	}
+	lines := func(first, last int) int {
+		total := 0
+		for i := first; i < last; i++ {
+			total += lipgloss.Height(rendered[i])
+		}
+		return total
+	}
+
+	first := 0
+	for first < selected && lines(first, selected+1) > height {
+		first++
+	}
+	last := first
+	for last < len(rendered) && (last <= selected || lines(first, last+1) <= height) {
+		last++
+	}
+	return first, last
+}
+
 // Helper function to wrap text
 func wrapText(text string, width int) string {
 	words := strings.Fields(text)
@@ -510,3 +1260,56 @@ func wrapText(text string, width int) string {
 	}
 	return text
 }
+
+// saveSnapshotCmd writes the given servers to a timestamped snapshot file in the current directory
+func saveSnapshotCmd(servers []MCPServer, format string) tea.Cmd {
+	return func() tea.Msg {
+		ext := "md"
+		if format == "json" {
+			ext = "json"
+		}
+		path := fmt.Sprintf("devgen_snapshot_%s.%s", time.Now().Format("20060102-150405"), ext)
+
+		var data []byte
+		if format == "json" {
+			var err error
+			data, err = json.MarshalIndent(servers, "", "  ")
+			if err != nil {
+				return snapshotSavedMsg{err: err}
+			}
+		} else {
+			data = []byte(renderSnapshotMarkdown(servers))
+		}
+
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return snapshotSavedMsg{err: err}
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return snapshotSavedMsg{path: path}
+	}
+}
+
+// renderSnapshotMarkdown renders servers as a markdown table for pasting into tickets
+func renderSnapshotMarkdown(servers []MCPServer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# MCP Server Snapshot (%s)\n\n", time.Now().Format(time.RFC3339))
+	b.WriteString("| Name | Status | Category | Tools | Endpoint |\n")
+	b.WriteString("|------|--------|----------|-------|----------|\n")
+	for _, server := range servers {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+			markdownCell(server.Name),
+			markdownCell(server.Status),
+			markdownCell(server.Metadata.Category),
+			len(server.Tools),
+			markdownCell(server.Endpoint))
+	}
+	fmt.Fprintf(&b, "\n%d servers\n", len(servers))
+	return b.String()
+}
+
+// markdownCell escapes pipes so values cannot break the table layout
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
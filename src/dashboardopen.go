@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// serverOpenedMsg reports the result of opening a server from the dashboard.
+// printed is set when the target could not be opened here and is only shown.
+type serverOpenedMsg struct {
+	target  string
+	printed bool
+	err     error
+}
+
+// serverOpenTarget returns what the dashboard opens for a server: a URL for its
+// HTTP health check or endpoint, or the script file of a stdio server
+func serverOpenTarget(server MCPServer) (target string, isURL bool, err error) {
+	if url, ok := healthCheckURL(server.Metadata.HealthCheck); ok {
+		return url, true, nil
+	}
+	scheme, err := endpointScheme(server.Endpoint)
+	if err != nil {
+		return "", false, err
+	}
+	switch scheme {
+	case "stdio":
+		argv := resolveStdioCommand(strings.TrimPrefix(server.Endpoint, "stdio://"))
+		return argv[len(argv)-1], false, nil
+	default:
+		return server.Endpoint, true, nil
+	}
+}
+
+// browserCommand returns the command that opens a URL on this desktop, or nil in
+// SSH sessions and on machines without a display
+func browserCommand(url string) *exec.Cmd {
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		return nil
+	}
+	var name string
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return nil
+		}
+		name = "xdg-open"
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return nil
+	}
+	return exec.Command(name, url)
+}
+
+// openServerCmd opens a server's URL in the browser or its script in $VISUAL or
+// $EDITOR. The editor takes over the terminal until it exits. When neither is
+// possible the target is reported so it can be copied instead.
+func openServerCmd(server MCPServer) tea.Cmd {
+	target, isURL, err := serverOpenTarget(server)
+	if err != nil {
+		return func() tea.Msg { return serverOpenedMsg{err: err} }
+	}
+
+	if isURL {
+		return func() tea.Msg {
+			cmd := browserCommand(target)
+			if cmd == nil || strings.HasPrefix(target, "ws") {
+				return serverOpenedMsg{target: target, printed: true}
+			}
+			if err := cmd.Start(); err != nil {
+				return serverOpenedMsg{target: target, err: err}
+			}
+			go cmd.Wait()
+			return serverOpenedMsg{target: target}
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	argv := strings.Fields(editor)
+	if len(argv) == 0 {
+		return func() tea.Msg { return serverOpenedMsg{target: target, printed: true} }
+	}
+	if _, err := os.Stat(target); err != nil {
+		return func() tea.Msg {
+			return serverOpenedMsg{target: target, err: fmt.Errorf("script not found: %s", target)}
+		}
+	}
+	cmd := exec.Command(argv[0], append(argv[1:], target)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return serverOpenedMsg{target: target, err: err}
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagedCommittedMsg reports the result of writing preview changes to the registry
+type stagedCommittedMsg struct {
+	changed int
+	err     error
+}
+
+// toggledStatus is the status a toggle moves a server to
+func toggledStatus(status string) string {
+	if isActiveStatus(status) {
+		return "inactive"
+	}
+	return "active"
+}
+
+// applyToggle toggles a server, staging the change in memory in preview mode
+func (m dashboardModel) applyToggle(server MCPServer) (tea.Model, tea.Cmd) {
+	if !m.preview {
+		return m, m.toggleServerCmd(server.Name)
+	}
+	m = m.stage(map[string]string{server.Name: toggledStatus(server.Status)})
+	m.statusMessage = fmt.Sprintf("Staged %s → %s (not saved)", server.Name, toggledStatus(server.Status))
+	return m, nil
+}
+
+// applyBulk sets status on the marked servers, staging the change in preview mode
+func (m dashboardModel) applyBulk(status string) (tea.Model, tea.Cmd) {
+	if !m.preview {
+		return m, bulkSetStatusCmd(m.markedNames(), status)
+	}
+	changes := make(map[string]string, len(m.marked))
+	for name := range m.marked {
+		changes[name] = status
+	}
+	m = m.stage(changes)
+	m.marked = map[string]bool{}
+	m.statusMessage = fmt.Sprintf("Staged %d servers → %s (not saved)", len(changes), status)
+	return m, nil
+}
+
+// stage records status changes by server name. A change back to the status on
+// disk is dropped, so toggling twice leaves nothing to save.
+func (m dashboardModel) stage(changes map[string]string) dashboardModel {
+	onDisk := make(map[string]string)
+	if m.registry != nil {
+		for _, server := range m.registry.Servers {
+			onDisk[server.Name] = server.Status
+		}
+	}
+
+	staged := make(map[string]string, len(m.staged)+len(changes))
+	for name, status := range m.staged {
+		staged[name] = status
+	}
+	for name, status := range changes {
+		if onDisk[name] == status {
+			delete(staged, name)
+		} else {
+			staged[name] = status
+		}
+	}
+	m.staged = staged
+	m.servers = m.visibleServers()
+	return m
+}
+
+// visibleServers applies staged changes to the loaded registry, then the
+// filters and grouping. The loaded registry itself is never modified.
+func (m dashboardModel) visibleServers() []MCPServer {
+	if m.registry == nil {
+		return []MCPServer{}
+	}
+	servers := make([]MCPServer, len(m.registry.Servers))
+	copy(servers, m.registry.Servers)
+	for i := range servers {
+		if status, ok := m.staged[servers[i].Name]; ok {
+			servers[i].Status = status
+		}
+	}
+	return groupServers(filterServers(servers, m.filterCategory, m.filterStatus), m.sortKey)
+}
+
+// commitStagedCmd writes every staged change with a single registry save
+func commitStagedCmd(staged map[string]string) tea.Cmd {
+	names := make([]string, 0, len(staged))
+	for name := range staged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func() tea.Msg {
+		registry, err := reloadRegistry()
+		if err != nil {
+			return stagedCommittedMsg{err: err}
+		}
+
+		type change struct{ name, oldStatus, newStatus string }
+		var changes []change
+		for i := range registry.Servers {
+			server := &registry.Servers[i]
+			status, ok := staged[server.Name]
+			if !ok || server.Status == status {
+				continue
+			}
+			changes = append(changes, change{server.Name, server.Status, status})
+			server.Status = status
+		}
+
+		if len(changes) > 0 {
+			if err := saveMCPRegistry(registry); err != nil {
+				return stagedCommittedMsg{err: err}
+			}
+			for _, c := range changes {
+				logRegistryEvent("toggle", c.name, c.oldStatus, c.newStatus)
+			}
+		}
+		return stagedCommittedMsg{changed: len(changes)}
+	}
+}
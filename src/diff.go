@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diffContext is how many unchanged lines surround each hunk
+const diffContext = 3
+
+var (
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#39FF14"))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF3131"))
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF"))
+	diffFileStyle   = lipgloss.NewStyle().Bold(true)
+)
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// diffLines returns the edit script turning a into b. Common leading and
+// trailing lines are stripped before the LCS table is built, so the table only
+// covers the region that actually changed.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lcs[i][j] is the longest common subsequence of midA[i:] and midB[j:]
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{' ', midA[i]})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removals come before additions, as in diff(1)
+			ops = append(ops, diffOp{'-', midA[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', midB[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// useDiffColor reports whether diffs written to stdout should be colored:
+// only on a terminal, and never with --no-color or NO_COLOR set
+func useDiffColor() bool {
+	return !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
+
+// renderUnifiedDiff renders the difference between two texts in unified diff
+// format with diffContext lines of context. It returns "" when they are equal.
+// With color, additions are green, removals red and hunk headers cyan.
+func renderUnifiedDiff(oldName, newName, oldText, newText string, color bool) string {
+	ops := diffLines(splitDiffLines(oldText), splitDiffLines(newText))
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	paint := func(style lipgloss.Style, text string) string {
+		if !color {
+			return text
+		}
+		return style.Render(text)
+	}
+
+	var b strings.Builder
+	b.WriteString(paint(diffFileStyle, "--- "+oldName) + "\n")
+	b.WriteString(paint(diffFileStyle, "+++ "+newName) + "\n")
+
+	// oldLine/newLine are the 1-based line numbers each op starts at
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	oldLine[0], newLine[0] = 1, 1
+	for k, op := range ops {
+		oldLine[k+1], newLine[k+1] = oldLine[k], newLine[k]
+		if op.kind != '+' {
+			oldLine[k+1]++
+		}
+		if op.kind != '-' {
+			newLine[k+1]++
+		}
+	}
+
+	for start := 0; start < len(ops); {
+		// Find the next change and grow the hunk until changes are more than
+		// two contexts apart
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		end := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		from := max(first-diffContext, start)
+		to := min(end+diffContext, len(ops))
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		b.WriteString(paint(diffHunkStyle, fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldLine[from], oldCount), hunkRange(newLine[from], newCount))) + "\n")
+		for _, op := range ops[from:to] {
+			line := string(op.kind) + op.text
+			switch op.kind {
+			case '+':
+				line = paint(diffAddStyle, line)
+			case '-':
+				line = paint(diffRemoveStyle, line)
+			}
+			b.WriteString(line + "\n")
+		}
+		start = to
+	}
+	return b.String()
+}
+
+// hunkRange formats the start,count part of a hunk header. An empty range
+// starts at the line before it, as diff(1) does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitDiffLines splits text into lines without a trailing empty line
+func splitDiffLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
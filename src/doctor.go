@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// strayDebugFiles are the debug logs earlier versions wrote into the working
+// directory. They are now written under the config directory (see openDebugLog).
+var strayDebugFiles = []string{
+	"key_debug.log",
+	"dashboard_debug.log",
+	"machina_debug.log",
+	"machina_logfire.jsonl",
+}
+
+// Doctor check outcomes
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorCheck is one line of the doctor checklist
+type doctorCheck struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// isPortAvailable reports whether host:port can be bound right now
+func isPortAvailable(host string, port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// findStrayDebugFiles returns debug files present in the working directory
+func findStrayDebugFiles() []string {
+	var found []string
+	for _, name := range strayDebugFiles {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+func checkRegistryFile() doctorCheck {
+	check := doctorCheck{Name: "Registry file"}
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return check
+	}
+	if len(registry.Servers) == 0 {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("%s (%s)", emptyRegistryMessage, configFile)
+		return check
+	}
+	if problems := validateMCPRegistry(registry); len(problems) > 0 {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s has %d problems: %s", configFile, len(problems), strings.Join(problems, "; "))
+		return check
+	}
+	if duplicates := duplicateEndpoints(registry.Servers); len(duplicates) > 0 && !allowDuplicateEndpoints {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("%s: %s", configFile, strings.Join(duplicates, "; "))
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = fmt.Sprintf("%s (%d servers)", configFile, len(registry.Servers))
+	return check
+}
+
+// checkPython verifies python3 is on PATH; Logfire events are sent through it
+func checkPython() doctorCheck {
+	check := doctorCheck{Name: "python3"}
+	path, err := exec.LookPath("python3")
+	if err != nil {
+		check.Status = doctorWarn
+		check.Detail = "not found on PATH; Logfire events cannot be sent"
+		if os.Getenv("LOGFIRE_WRITE_TOKEN") != "" {
+			check.Status = doctorFail
+		}
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = path
+	return check
+}
+
+func checkLogfireToken() doctorCheck {
+	check := doctorCheck{Name: "Logfire token"}
+	if os.Getenv("LOGFIRE_WRITE_TOKEN") == "" {
+		check.Status = doctorWarn
+		check.Detail = "LOGFIRE_WRITE_TOKEN is not set; events are only written locally"
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = "LOGFIRE_WRITE_TOKEN is set"
+	return check
+}
+
+func checkSSHPort() doctorCheck {
+	check := doctorCheck{Name: "SSH port"}
+	if !isPortAvailable(sshHost, sshPort) {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("%s:%d is already in use", sshHost, sshPort)
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = fmt.Sprintf("%s:%d is free", sshHost, sshPort)
+	return check
+}
+
+func checkStrayFiles(stray []string) doctorCheck {
+	check := doctorCheck{Name: "Stray debug files"}
+	if len(stray) == 0 {
+		check.Status = doctorPass
+		check.Detail = "none found"
+		return check
+	}
+	check.Status = doctorWarn
+	check.Detail = fmt.Sprintf("%d found (run with --clean to remove)", len(stray))
+	return check
+}
+
+// renderDoctorCheck formats one checklist line with a colored marker
+func renderDoctorCheck(check doctorCheck) string {
+	marker := statusRunning.Render("✓")
+	switch check.Status {
+	case doctorWarn:
+		marker = headerStyle.Render("!")
+	case doctorFail:
+		marker = statusStopped.Render("✗")
+	}
+	return fmt.Sprintf("%s %-18s %s\n", marker, check.Name, check.Detail)
+}
+
+// runDoctor prints the diagnostic checklist and optionally removes stray debug files.
+// It returns an error when any check fails so scripts can rely on the exit code.
+func runDoctor(clean bool) error {
+	stray := findStrayDebugFiles()
+
+	checks := []doctorCheck{
+		checkRegistryFile(),
+		checkPython(),
+		checkLogfireToken(),
+		checkSSHPort(),
+		checkStrayFiles(stray),
+	}
+
+	printProgress("🩺 DevGen Doctor\n\n")
+	for _, check := range checks {
+		printResult("%s", renderDoctorCheck(check))
+	}
+
+	if len(stray) > 0 {
+		cwd, _ := os.Getwd()
+		printResult("\n")
+		for _, name := range stray {
+			printResult("   • %s\n", filepath.Join(cwd, name))
+		}
+	}
+
+	if clean && len(stray) > 0 {
+		printResult("\n")
+		removed := 0
+		for _, name := range stray {
+			if err := os.Remove(name); err != nil {
+				printResult("❌ Failed to remove %s: %v\n", name, err)
+				continue
+			}
+			removed++
+		}
+		printResult("🧹 Removed %d of %d stray files\n", removed, len(stray))
+	}
+
+	var passed, warned, failed int
+	for _, check := range checks {
+		switch check.Status {
+		case doctorPass:
+			passed++
+		case doctorWarn:
+			warned++
+		case doctorFail:
+			failed++
+		}
+	}
+	printResult("\n%d passed, %d warnings, %d failed\n", passed, warned, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d failing checks", failed)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by devgen so scripts can tell failures apart
+const (
+	exitOK            = 0
+	exitFailure       = 1 // runtime failure, or unhealthy servers for `registry health`
+	exitRegistryError = 2 // the registry file could not be read or parsed
+	exitUsage         = 3 // bad flags or arguments
+	exitNotFound      = 4 // a named server or tool does not exist
+	exitValidation    = 5 // input was read but failed validation
+)
+
+// exitError carries a specific exit code up to main
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps err so main exits with the given code
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode maps an error returned from a command to the process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	// cobra reports unknown subcommands before any hook of ours runs
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		return exitUsage
+	}
+	return exitFailure
+}
+
+// usageError reports bad flags or arguments
+func usageError(format string, args ...interface{}) error {
+	return withExitCode(exitUsage, fmt.Errorf(format, args...))
+}
+
+// notFoundError reports a named server or tool that does not exist
+func notFoundError(format string, args ...interface{}) error {
+	return withExitCode(exitNotFound, fmt.Errorf(format, args...))
+}
+
+// validationError reports input that was read but is not acceptable
+func validationError(format string, args ...interface{}) error {
+	return withExitCode(exitValidation, fmt.Errorf(format, args...))
+}
+
+// registryLoadError reports a registry file that could not be read or parsed
+func registryLoadError(err error) error {
+	return withExitCode(exitRegistryError, fmt.Errorf("failed to load registry: %v", err))
+}
+
+// classifyUsageErrors makes flag and argument errors from every command in the
+// tree exit with exitUsage
+func classifyUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return withExitCode(exitUsage, err)
+	})
+
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			return withExitCode(exitUsage, validate(c, args))
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		classifyUsageErrors(child)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// exportOptions are the settings for `registry export`
+type exportOptions struct {
+	format      string
+	output      string
+	withHealth  bool
+	concurrency int
+	timeout     time.Duration
+}
+
+// exportHealth is the live health attached to each server with --with-health
+type exportHealth struct {
+	Healthy   bool   `json:"healthy"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// exportedServer is a registry server plus optional live health. The embedded
+// server keeps the registry file layout so a JSON export can be imported again.
+type exportedServer struct {
+	MCPServer
+	Health *exportHealth `json:"health,omitempty"`
+}
+
+// healthResults probes servers with at most concurrency probes in flight and
+// returns one channel per server, so results can be written in registry order
+// as soon as each one is ready
+func healthResults(servers []MCPServer, concurrency int, timeout time.Duration) []chan exportHealth {
+	results := make([]chan exportHealth, len(servers))
+	for i := range results {
+		results[i] = make(chan exportHealth, 1)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	go func() {
+		for i, server := range servers {
+			sem <- struct{}{}
+			go func(i int, server MCPServer) {
+				defer func() { <-sem }()
+				start := time.Now()
+				err := probeServer(server, timeout)
+				health := exportHealth{Healthy: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+				if err != nil {
+					health.Error = err.Error()
+				}
+				results[i] <- health
+			}(i, server)
+		}
+	}()
+	return results
+}
+
+// exportRegistry streams the registry as JSON or CSV to --output (stdout by default).
+// Servers are written one at a time instead of marshaling the whole export.
+func exportRegistry(opts exportOptions) error {
+	if opts.format != "json" && opts.format != "csv" {
+		return usageError("invalid --format value %q (expected json or csv)", opts.format)
+	}
+	if opts.concurrency < 1 {
+		return usageError("--concurrency must be at least 1")
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.output != "" && opts.output != "-" {
+		file, err := os.Create(opts.output)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	w := bufio.NewWriter(out)
+
+	var health []chan exportHealth
+	if opts.withHealth {
+		defer startTiming("export health probes")()
+		health = healthResults(registry.Servers, opts.concurrency, opts.timeout)
+	}
+	next := func(i int) exportedServer {
+		server := exportedServer{MCPServer: registry.Servers[i]}
+		if health != nil {
+			h := <-health[i]
+			server.Health = &h
+		}
+		return server
+	}
+
+	if opts.format == "csv" {
+		err = writeExportCSV(w, registry, opts.withHealth, next)
+	} else {
+		err = writeExportJSON(w, registry, next)
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %v", err)
+	}
+
+	if opts.output != "" && opts.output != "-" {
+		printProgress("📤 Exported %d servers to %s\n", len(registry.Servers), opts.output)
+	}
+	return nil
+}
+
+// writeExportJSON writes the registry document, encoding servers and tools one by one
+func writeExportJSON(w io.Writer, registry *MCPRegistry, next func(int) exportedServer) error {
+	version, err := json.Marshal(registry.Version)
+	if err != nil {
+		return err
+	}
+	timestamp, err := json.Marshal(registry.Timestamp)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "{\n  \"version\": %s,\n  \"timestamp\": %s,\n  \"servers\": [", version, timestamp)
+
+	for i := range registry.Servers {
+		data, err := json.Marshal(next(i))
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, "\n    %s", data)
+	}
+	fmt.Fprint(w, "\n  ],\n  \"tools\": [")
+
+	for i, tool := range registry.Tools {
+		data, err := json.Marshal(tool)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, "\n    %s", data)
+	}
+	_, err = fmt.Fprint(w, "\n  ]\n}\n")
+	return err
+}
+
+// writeExportCSV writes one row per server
+func writeExportCSV(w io.Writer, registry *MCPRegistry, withHealth bool, next func(int) exportedServer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"name", "status", "category", "framework", "endpoint", "version", "tools", "registered_at", "last_seen", "health_check_failures"}
+	if withHealth {
+		header = append(header, "healthy", "latency_ms", "health_error")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := range registry.Servers {
+		server := next(i)
+		lastSeen := ""
+		if server.LastSeen != nil {
+			lastSeen = *server.LastSeen
+		}
+		row := []string{
+			server.Name,
+			server.Status,
+			server.Metadata.Category,
+			server.Metadata.Framework,
+			server.Endpoint,
+			server.Version,
+			strconv.Itoa(len(server.Tools)),
+			server.RegisteredAt,
+			lastSeen,
+			strconv.Itoa(server.HealthCheckFails),
+		}
+		if server.Health != nil {
+			row = append(row, strconv.FormatBool(server.Health.Healthy), strconv.FormatInt(server.Health.LatencyMs, 10), server.Health.Error)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		// Flush per row so output streams instead of accumulating
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// healthCheckCommandPrefix marks a metadata.health_check that is a command to run
+const healthCheckCommandPrefix = "cmd:"
+
+// healthCheckWaitDelay bounds how long a timed-out health check command may keep
+// its output pipes open, e.g. through a child process that outlived it
+const healthCheckWaitDelay = time.Second
+
+// healthCheckCommand returns the argv of a "cmd:" health check, or nil when the
+// health check is not in command form. Arguments are split on whitespace; no
+// shell is involved.
+func healthCheckCommand(healthCheck string) []string {
+	command, ok := strings.CutPrefix(strings.TrimSpace(healthCheck), healthCheckCommandPrefix)
+	if !ok {
+		return nil
+	}
+	argv := strings.Fields(command)
+	if len(argv) == 0 {
+		return []string{}
+	}
+	return argv
+}
+
+// healthCheckURL returns the health check when it is an HTTP or WebSocket URL
+func healthCheckURL(healthCheck string) (string, bool) {
+	healthCheck = strings.TrimSpace(healthCheck)
+	scheme, err := endpointScheme(healthCheck)
+	if err != nil || scheme == "stdio" {
+		return "", false
+	}
+	return healthCheck, true
+}
+
+// probeServer checks a server the way it declares in metadata.health_check:
+// "cmd:<command>" runs the command and treats exit 0 as healthy, an http(s) or
+// ws(s) URL is probed instead of the endpoint, and anything else (including the
+// descriptive names older registries use) falls back to probing the endpoint.
+func probeServer(server MCPServer, timeout time.Duration) error {
+	if argv := healthCheckCommand(server.Metadata.HealthCheck); argv != nil {
+		return runHealthCheckCommand(server, argv, timeout)
+	}
+	if url, ok := healthCheckURL(server.Metadata.HealthCheck); ok {
+		return probeMCPEndpoint(url, timeout)
+	}
+	return probeMCPEndpoint(server.Endpoint, timeout)
+}
+
+// runHealthCheckCommand runs a health check command with a timeout. The server
+// name and endpoint are passed as DEVGEN_SERVER_NAME and DEVGEN_SERVER_ENDPOINT.
+func runHealthCheckCommand(server MCPServer, argv []string, timeout time.Duration) error {
+	if len(argv) == 0 {
+		return fmt.Errorf("health check %q has no command", server.Metadata.HealthCheck)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(),
+		"DEVGEN_SERVER_NAME="+server.Name,
+		"DEVGEN_SERVER_ENDPOINT="+server.Endpoint,
+	)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = healthCheckWaitDelay
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("health check command timed out after %s", timeout)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = fmt.Errorf("health check command exited with status %d", exitErr.ExitCode())
+	} else {
+		err = fmt.Errorf("health check command failed: %v", err)
+	}
+	// The last line of output usually says why
+	if lines := strings.Split(strings.TrimSpace(output.String()), "\n"); lines[len(lines)-1] != "" {
+		err = fmt.Errorf("%v: %s", err, truncateText(lines[len(lines)-1], 200))
+	}
+	return err
+}
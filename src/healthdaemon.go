@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// healthDaemonOptions configure registry health --daemon
+type healthDaemonOptions struct {
+	interval time.Duration
+	listen   string
+	pidFile  string
+	logfire  bool
+}
+
+// healthSnapshot is the latest completed pass, served by the daemon's HTTP endpoint
+type healthSnapshot struct {
+	Pass      int            `json:"pass"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Healthy   int            `json:"healthy"`
+	Unhealthy int            `json:"unhealthy"`
+	Servers   []serverHealth `json:"servers"`
+}
+
+func defaultHealthPIDFile() string {
+	return filepath.Join(devgenDir(), "health.pid")
+}
+
+// writePIDFile records the current process in path, refusing to start when it
+// names another process that is still running. A stale file is replaced.
+func writePIDFile(path string) error {
+	if data, err := os.ReadFile(path); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid != os.Getpid() && processRunning(pid) {
+			return fmt.Errorf("health daemon already running (pid %d, %s)", pid, path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create PID file directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
+// processRunning reports whether a process with this pid exists
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// healthHandler serves the latest snapshot as JSON. It answers 503 until the
+// first pass completes and while any server is unhealthy, so a load balancer
+// or uptime monitor can use the status code alone.
+func healthHandler(mu *sync.RWMutex, latest **healthSnapshot) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		mu.RLock()
+		snapshot := *latest
+		mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if snapshot == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": "no health pass has completed yet"})
+			return
+		}
+		if snapshot.Unhealthy > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(snapshot)
+	}
+}
+
+// runHealthDaemon runs a health pass every interval without a TUI until it
+// receives SIGINT or SIGTERM. Results are recorded in the registry by each
+// pass; changes in health are logged and, with logfire, sent to Logfire. A
+// registry that cannot be read skips that pass rather than stopping the daemon.
+func runHealthDaemon(opts healthPassOptions, daemon healthDaemonOptions) error {
+	if daemon.interval <= 0 {
+		return usageError("--interval must be positive")
+	}
+	if daemon.pidFile == "" {
+		daemon.pidFile = defaultHealthPIDFile()
+	}
+
+	if err := writePIDFile(daemon.pidFile); err != nil {
+		return err
+	}
+	defer os.Remove(daemon.pidFile)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var mu sync.RWMutex
+	var latest *healthSnapshot
+
+	serveErr := make(chan error, 1)
+	if daemon.listen != "" {
+		listener, err := net.Listen("tcp", daemon.listen)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", daemon.listen, err)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/health", healthHandler(&mu, &latest))
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- err
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+		log.Info("Serving health results", "url", "http://"+listener.Addr().String()+"/health")
+	}
+
+	log.Info("Health daemon started", "pid", os.Getpid(), "interval", daemon.interval, "pid_file", daemon.pidFile)
+	if daemon.logfire {
+		logToLogfire("info", "Health daemon started", map[string]interface{}{
+			"interval": daemon.interval.String(),
+		})
+	}
+
+	previous := make(map[string]bool)
+	for pass := 1; ; pass++ {
+		// Re-read each pass so servers added or toggled elsewhere are picked up
+		registry, err := reloadRegistry()
+		if err != nil {
+			log.Error("Skipping health pass", "pass", pass, "error", err)
+		} else {
+			checkedAt := time.Now()
+			results, unhealthy := runHealthPass(registry, opts)
+
+			mu.Lock()
+			latest = &healthSnapshot{
+				Pass:      pass,
+				CheckedAt: checkedAt,
+				Healthy:   len(results) - unhealthy,
+				Unhealthy: unhealthy,
+				Servers:   results,
+			}
+			mu.Unlock()
+
+			for _, result := range results {
+				if change := healthChange(result, previous); change != "" {
+					logHealthChange(result, change, daemon.logfire)
+				}
+				previous[result.Name] = result.Healthy
+			}
+			log.Info("Health pass complete", "pass", pass, "healthy", len(results)-unhealthy, "unhealthy", unhealthy)
+			if daemon.logfire {
+				logToLogfire("info", "Health pass complete", map[string]interface{}{
+					"pass":      pass,
+					"healthy":   len(results) - unhealthy,
+					"unhealthy": unhealthy,
+				})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Health daemon stopping")
+			return nil
+		case err := <-serveErr:
+			return fmt.Errorf("health endpoint failed: %w", err)
+		case <-time.After(daemon.interval):
+		}
+	}
+}
+
+// logHealthChange reports a server that went down or recovered
+func logHealthChange(result serverHealth, change string, logfire bool) {
+	if change == "down" {
+		log.Warn("Server went down", "server", result.Name, "error", result.Error)
+	} else {
+		log.Info("Server recovered", "server", result.Name, "latency_ms", result.LatencyMs)
+	}
+	if !logfire {
+		return
+	}
+	level := "info"
+	if change == "down" {
+		level = "warning"
+	}
+	logToLogfire(level, "MCP server health changed", map[string]interface{}{
+		"server":   result.Name,
+		"change":   change,
+		"healthy":  result.Healthy,
+		"error":    result.Error,
+		"failures": result.Failures,
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// clearScreen moves the cursor home and clears the terminal so each pass redraws in place
+const clearScreen = "\033[H\033[2J"
+
+// healthChangeLabels are shown in the CHANGE column of the follow table
+var healthChangeLabels = map[string]string{
+	"down":      "▼ went down",
+	"recovered": "▲ recovered",
+}
+
+// healthChange reports how a server's health moved since the previous pass:
+// "down", "recovered", or "" when it is unchanged or newly seen
+func healthChange(result serverHealth, previous map[string]bool) string {
+	wasHealthy, seen := previous[result.Name]
+	switch {
+	case !seen:
+		return ""
+	case wasHealthy && !result.Healthy:
+		return "down"
+	case !wasHealthy && result.Healthy:
+		return "recovered"
+	}
+	return ""
+}
+
+// followRegistryHealth runs a health pass every interval until interrupted.
+// On a terminal the table is redrawn in place; otherwise each pass is appended.
+// With --json every pass is written as one JSON object per line.
+func followRegistryHealth(opts healthPassOptions, interval time.Duration, asJSON bool) error {
+	if interval <= 0 {
+		return usageError("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	redraw := isTerminal(os.Stdout) && !asJSON
+	previous := make(map[string]bool)
+	for pass := 1; ; pass++ {
+		// Re-read each pass so servers added or toggled elsewhere are picked up
+		registry, err := reloadRegistry()
+		if err != nil {
+			return registryLoadError(err)
+		}
+		checkedAt := time.Now()
+		results, unhealthy := runHealthPass(registry, opts)
+
+		if asJSON {
+			changes := make(map[string]string)
+			for _, result := range results {
+				if change := healthChange(result, previous); change != "" {
+					changes[result.Name] = change
+				}
+			}
+			data, err := json.Marshal(map[string]interface{}{
+				"pass":       pass,
+				"checked_at": checkedAt.Format(time.RFC3339),
+				"healthy":    len(results) - unhealthy,
+				"unhealthy":  unhealthy,
+				"changed":    changes,
+				"servers":    results,
+			})
+			if err != nil {
+				return err
+			}
+			printJSON(data)
+		} else {
+			headers := []string{"NAME", "HEALTH", "LATENCY", "FAILS", "CHANGE", "DETAIL"}
+			cells := make([][]string, 0, len(results))
+			for _, result := range results {
+				health := "healthy"
+				if !result.Healthy {
+					health = "unhealthy"
+				}
+				cells = append(cells, []string{result.Name, health, fmt.Sprintf("%dms", result.LatencyMs), strconv.Itoa(result.Failures), healthChangeLabels[healthChange(result, previous)], result.Error})
+			}
+
+			if redraw {
+				fmt.Print(clearScreen)
+				printProgress("🏥 MCP Server Health — pass %d at %s, every %s (Ctrl+C to stop)\n\n", pass, checkedAt.Format("15:04:05"), interval)
+				printResult("%s\n", renderTable(headers, cells, -1))
+			} else {
+				printProgress("🏥 Pass %d at %s\n", pass, checkedAt.Format(time.RFC3339))
+				printResult("%s", renderTSV(headers, cells))
+			}
+			printResult("%d/%d servers healthy\n", len(results)-unhealthy, len(results))
+		}
+
+		for _, result := range results {
+			previous[result.Name] = result.Healthy
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// healthProbedMsg is sent to the progress view each time a probe finishes
+type healthProbedMsg struct {
+	done, total int
+	result      serverHealth
+}
+
+// healthPassDoneMsg ends the progress view once the pass has been recorded
+type healthPassDoneMsg struct{}
+
+// healthProgressModel shows a health pass as a progress bar with a running count
+type healthProgressModel struct {
+	bar       progress.Model
+	done      int
+	total     int
+	unhealthy int
+	last      string
+	finished  bool
+}
+
+func (m healthProgressModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m healthProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case healthProbedMsg:
+		m.done, m.total = msg.done, msg.total
+		m.last = msg.result.Name
+		if !msg.result.Healthy {
+			m.unhealthy++
+		}
+	case healthPassDoneMsg:
+		// Clear the bar so the results table starts on a clean screen
+		m.finished = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m healthProgressModel) View() string {
+	if m.finished {
+		return ""
+	}
+	percent := 0.0
+	if m.total > 0 {
+		percent = float64(m.done) / float64(m.total)
+	}
+	line := fmt.Sprintf("🏥 Checking servers %s %d/%d", m.bar.ViewAs(percent), m.done, m.total)
+	if m.unhealthy > 0 {
+		line += fmt.Sprintf(", %d unhealthy", m.unhealthy)
+	}
+	if m.last != "" {
+		line += "  " + m.last
+	}
+	return stripEmojiIfDisabled(line) + "\n"
+}
+
+// showHealthProgress reports whether a health pass should draw a progress bar:
+// only for an interactive session that is not --quiet
+func showHealthProgress() bool {
+	return !quiet && isTerminal(os.Stderr) && isTerminal(os.Stdout)
+}
+
+// runHealthPassWithProgress runs the same health pass as runHealthPass while
+// a progress bar on stderr counts servers as their probes finish
+func runHealthPassWithProgress(registry *MCPRegistry, opts healthPassOptions) ([]serverHealth, int) {
+	model := healthProgressModel{bar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(30))}
+	program := tea.NewProgram(model, tea.WithOutput(os.Stderr), tea.WithInput(nil))
+
+	var results []serverHealth
+	var unhealthy int
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		passOpts := opts
+		passOpts.onProbed = func(done, total int, result serverHealth) {
+			program.Send(healthProbedMsg{done: done, total: total, result: result})
+		}
+		results, unhealthy = runHealthPass(registry, passOpts)
+		program.Send(healthPassDoneMsg{})
+	}()
+
+	// The view is cosmetic: if it fails the pass still completes
+	program.Run()
+	<-finished
+	return results, unhealthy
+}
@@ -9,17 +9,22 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // Global flags
@@ -32,8 +37,57 @@ var (
 	sshHost      string
 	registryURL  string
 	useRegistry  bool
+
+	sshAuditLog     string
+	sshAuditLogfire bool
+	sshIdleTimeout  time.Duration
+	sshMaxSession   time.Duration
+
+	sshMaxAuthFailures int
+	sshAuthWindow      time.Duration
+	sshAuthCooldown    time.Duration
+	sshReadOnly        bool
+	sshAllowToggle     bool
+	sshRefreshRegistry bool
+	sshAuthBackend     string
+	sshAuthorizedKeys  string
+	sshCheckOnly       bool
+	showTimings        bool
+	configDir          string
+
+	allowDuplicateEndpoints bool
+
+	quiet   bool
+	noEmoji bool
+	noColor bool
+
+	registrySaveTo string
+
+	redactValues []string
 )
 
+// loadedEnvFile is the .env file picked up at startup, reported once flags are parsed
+var loadedEnvFile string
+
+// SSH commands that display registry state; refreshed with --ssh-refresh-registry
+var sshReadCommands = map[string]bool{
+	"list":   true,
+	"status": true,
+	"health": true,
+}
+
+// SSH commands that change registry state; disabled in read-only mode
+var sshMutatingCommands = map[string]bool{
+	"toggle": true,
+}
+
+// sshReadOnlyMode reports whether mutating SSH commands are disabled. They are
+// off unless --ssh-allow-toggle is given, since the default demo
+// authentication accepts any public key, and --ssh-read-only always wins.
+func sshReadOnlyMode() bool {
+	return sshReadOnly || !sshAllowToggle
+}
+
 // MCP Server types
 type MCPServer struct {
 	Name              string      `json:"name"`
@@ -72,6 +126,11 @@ type MCPTool struct {
 	LastUsed    string `json:"last_used"`
 }
 
+// isActiveStatus reports whether a server status counts as running
+func isActiveStatus(status string) bool {
+	return status == "active" || status == "production-ready" || status == "running"
+}
+
 // Dashboard types
 // Dashboard types moved to dashboard.go
 
@@ -102,9 +161,80 @@ var (
 		Bold(true)
 )
 
+// logfirePython is the python3 used to send logs to Logfire, or "" when none
+// is installed and logs only go to the local JSONL fallback. It is looked up
+// once, on the first log, so commands that never log do not pay for it.
+var (
+	logfirePython     string
+	logfirePythonOnce sync.Once
+)
+
+// logfireInFlight tracks events still being sent, so a one-shot command that
+// exits right after a mutation does not drop its audit event
+var logfireInFlight sync.WaitGroup
+
+// logfireFlushTimeout bounds how long main waits for in-flight events on exit
+const logfireFlushTimeout = 3 * time.Second
+
+// waitForLogfire waits for in-flight Logfire events, giving up after timeout
+func waitForLogfire(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		logfireInFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func findLogfirePython() string {
+	logfirePythonOnce.Do(func() {
+		path, err := exec.LookPath("python3")
+		if err != nil {
+			log.Warn("python3 not found; Logfire events are only written to machina_logfire.jsonl", "dir", devgenDir())
+			return
+		}
+		logfirePython = path
+	})
+	return logfirePython
+}
+
 // Logfire integration - send logs to logfire-mcp server
 func logToLogfire(level, message string, extra map[string]interface{}) {
+	message = maskSecrets(message)
+	extra = maskFields(extra)
+	python := findLogfirePython()
+
+	logfireInFlight.Add(1)
 	go func() {
+		defer logfireInFlight.Done()
+
+		// Write the local copy first so it survives a slow or hanging Python send
+		logFile, err := openDebugLog("machina_logfire.jsonl")
+		if err == nil {
+			logData := map[string]interface{}{
+				"timestamp": time.Now().Format(time.RFC3339),
+				"level":     level,
+				"message":   message,
+				"service":   "machina-cli",
+				"component": "main",
+				"project":   os.Getenv("LOGFIRE_PROJECT_NAME"),
+			}
+			for k, v := range extra {
+				logData[k] = v
+			}
+			jsonData, _ := json.Marshal(logData)
+			logFile.WriteString(string(jsonData) + "\n")
+			logFile.Close()
+		}
+		
+		// Also write to debug log
+		debugFile, _ := openDebugLog("machina_debug.log")
+		fmt.Fprintf(debugFile, "[LOGFIRE] %s: %s\n", level, message)
+		debugFile.Close()
+
 		// Try to send to logfire-mcp server via HTTP
 		requestData := map[string]interface{}{
 			"level":      level,
@@ -115,7 +245,8 @@ func logToLogfire(level, message string, extra map[string]interface{}) {
 		jsonData, _ := json.Marshal(requestData)
 		
 		// Send to Logfire via clean Python subprocess
-		cmd := exec.Command("python3", "-c", fmt.Sprintf(`
+		if python != "" {
+			cmd := exec.Command(python, "-c", fmt.Sprintf(`
 import os, sys, json
 sys.path.append('src')
 os.environ['LOGFIRE_TOKEN'] = os.getenv('LOGFIRE_WRITE_TOKEN', '')
@@ -135,36 +266,37 @@ elif data['level'] == 'error':
 else:
     logfire.info(data['message'], level=data['level'], **extra)
 `, string(jsonData)))
-		
-		cmd.Dir = "/Users/dionedge/devqai/machina"
-		cmd.Run() // Ignore errors for non-blocking
-		
-		// Fallback: write to local file for debugging
-		logFile, err := os.OpenFile("machina_logfire.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err == nil {
-			logData := map[string]interface{}{
-				"timestamp": time.Now().Format(time.RFC3339),
-				"level":     level,
-				"message":   message,
-				"service":   "machina-cli",
-				"component": "main",
-				"project":   os.Getenv("LOGFIRE_PROJECT_NAME"),
-			}
-			for k, v := range extra {
-				logData[k] = v
-			}
-			jsonData, _ := json.Marshal(logData)
-			logFile.WriteString(string(jsonData) + "\n")
-			logFile.Close()
+			cmd.Dir = "/Users/dionedge/devqai/machina"
+			cmd.Run() // Ignore errors for non-blocking
 		}
-		
-		// Also write to debug log
-		debugFile, _ := os.OpenFile("machina_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		fmt.Fprintf(debugFile, "[LOGFIRE] %s: %s\n", level, message)
-		debugFile.Close()
 	}()
 }
 
+// logRegistryEvent emits a structured audit event for a registry mutation.
+// Like logToLogfire it never blocks or fails the calling operation.
+func logRegistryEvent(action, serverName, oldStatus, newStatus string) {
+	logToLogfire("info", "Registry "+action, map[string]interface{}{
+		"event":       "registry_mutation",
+		"action":      action,
+		"server_name": serverName,
+		"old_status":  oldStatus,
+		"new_status":  newStatus,
+		"actor":       currentActor(),
+		"config_file": configFile,
+	})
+}
+
+// currentActor identifies who is running devgen for audit purposes
+func currentActor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	if user := os.Getenv("USERNAME"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
 func main() {
 	// Load environment variables from .env file
 	loadEnvFile()
@@ -197,12 +329,25 @@ servers and AI-powered development tools.
 For more information, visit: https://github.com/devq-ai/devgen-cli`,
 		Version: "1.0.0",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := compileRedactPatterns(redactValues); err != nil {
+				return err
+			}
+			if err := startProfiling(); err != nil {
+				return err
+			}
+			if noColor || os.Getenv("NO_COLOR") != "" {
+				lipgloss.SetColorProfile(termenv.Ascii)
+			}
+			if loadedEnvFile != "" {
+				printProgress("📄 Loaded environment variables from: %s\n", loadedEnvFile)
+			}
 			return setupLogging(logger)
 		},
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "mcp_status.json", "config file path")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "mcp_status.json", "registry file path (comma-separated list or glob to merge several)")
+	rootCmd.PersistentFlags().StringVar(&registrySaveTo, "save-to", "", "with several --config files, write all changes to this file instead of each server's own file")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().BoolVar(&sshMode, "ssh", false, "start SSH server for terminal access")
@@ -210,18 +355,48 @@ For more information, visit: https://github.com/devq-ai/devgen-cli`,
 	rootCmd.PersistentFlags().StringVar(&sshHost, "ssh-host", "localhost", "SSH server host")
 	rootCmd.PersistentFlags().StringVar(&registryURL, "registry-url", "http://127.0.0.1:31337", "MCP registry URL")
 	rootCmd.PersistentFlags().BoolVar(&useRegistry, "use-registry", false, "use MCP registry for server management")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress progress output, printing only errors and results")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "strip emoji from plain-text output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().StringSliceVar(&redactValues, "redact", nil, "extra field names or regex patterns to mask in logs (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "", "directory for devgen state such as the audit log (default $DEVGEN_CONFIG_DIR, ~/.devgen or $XDG_CONFIG_HOME/devgen)")
+	rootCmd.PersistentFlags().BoolVar(&allowDuplicateEndpoints, "allow-duplicate-endpoints", false, "do not warn about or reject servers that share an endpoint")
+	rootCmd.PersistentFlags().StringVar(&pprofCPUFile, "pprof-cpu", "", "write a CPU profile of the command to this file")
+	rootCmd.PersistentFlags().StringVar(&pprofMemFile, "pprof-mem", "", "write a heap profile to this file when the command finishes")
+	rootCmd.PersistentFlags().MarkHidden("pprof-cpu")
+	rootCmd.PersistentFlags().MarkHidden("pprof-mem")
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "print how long each phase took to stderr when the command finishes")
 
 	// Add core commands
 	rootCmd.AddCommand(
 		newDashboardCmd(),
 		newRegistryCmd(),
+		newToolCmd(),
 		newSSHCmd(),
+		newDoctorCmd(),
+		newAuditCmd(),
+		newAliasCmd(),
 		newHelpCmd(),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	// Bad flags and arguments anywhere in the tree exit with exitUsage
+	classifyUsageErrors(rootCmd)
+
+	// Expand configured aliases before cobra picks a command
+	args, err := resolveAliases(rootCmd, os.Args[1:])
+	if err != nil {
+		logger.Error("Command execution failed", "error", err)
+		os.Exit(exitCode(err))
+	}
+	rootCmd.SetArgs(args)
+
+	err = rootCmd.Execute()
+	stopProfiling()
+	printTimings()
+	waitForLogfire(logfireFlushTimeout)
+	if err != nil {
 		logger.Error("Command execution failed", "error", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -242,16 +417,39 @@ func setupLogging(logger *log.Logger) error {
 
 // Dashboard command
 func newDashboardCmd() *cobra.Command {
+	var opts dashboardOptions
+
 	cmd := &cobra.Command{
 		Use:     "dashboard",
 		Aliases: []string{"dash", "d"},
 		Short:   "Launch interactive dashboard",
 		Long:    "Launch the interactive terminal dashboard for managing MCP servers.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDashboard()
+			switch opts.confirmToggles {
+			case "always", "deactivate", "never":
+			default:
+				return usageError("invalid --confirm-toggles value %q (expected always, deactivate or never)", opts.confirmToggles)
+			}
+			if opts.sortKey != "" {
+				if err := validateSortKey(opts.sortKey); err != nil {
+					return err
+				}
+			}
+			if opts.readOnly && opts.noSave {
+				return usageError("--read-only and --no-save cannot be used together")
+			}
+			return runDashboard(opts)
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.category, "category", "", "only show servers in this category")
+	cmd.Flags().StringVar(&opts.status, "status", "", "only show servers with this status (e.g. active, inactive); active also matches production-ready and running")
+	cmd.Flags().StringVar(&opts.confirmToggles, "confirm-toggles", "deactivate", "ask before toggling: always, deactivate or never")
+	cmd.Flags().StringVar(&opts.sortKey, "sort", "", "sort servers within each group by name, status, tools or last-seen")
+	cmd.Flags().BoolVar(&opts.noSave, "no-save", false, "start in preview mode: toggles stay in memory until you press 'w' to save them")
+	cmd.Flags().BoolVar(&opts.readOnly, "read-only", false, "disable toggles and other changes to the registry, e.g. while screen-sharing")
+	cmd.Flags().BoolVar(&opts.rememberState, "remember-state", false, "restore and save filter and collapsed groups in dashboard_state.json in the config directory")
+
 	return cmd
 }
 
@@ -263,6 +461,10 @@ func newSSHCmd() *cobra.Command {
 		Short:   "Start SSH server for remote terminal access",
 		Long:    "Start an SSH server that provides secure remote terminal access to DevGen CLI commands. Essential for public-facing deployments.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if sshCheckOnly {
+				cmd.SilenceUsage = true
+				return runSSHCheck()
+			}
 			log.Info("Starting SSH server", "host", sshHost, "port", sshPort)
 			return startSSHServer()
 		},
@@ -270,6 +472,63 @@ func newSSHCmd() *cobra.Command {
 
 	cmd.Flags().IntVar(&sshPort, "ssh-port", 2222, "SSH server port")
 	cmd.Flags().StringVar(&sshHost, "ssh-host", "localhost", "SSH server host")
+	cmd.Flags().StringVar(&sshAuditLog, "ssh-audit-log", "", "audit log file (default audit.jsonl in the config directory)")
+	cmd.Flags().BoolVar(&sshAuditLogfire, "ssh-audit-logfire", false, "also send audit events to Logfire")
+	cmd.Flags().DurationVar(&sshIdleTimeout, "ssh-idle-timeout", 10*time.Minute, "close sessions with no command for this long (0 disables)")
+	cmd.Flags().DurationVar(&sshMaxSession, "ssh-max-session", 0, "maximum total session duration (0 disables)")
+	cmd.Flags().IntVar(&sshMaxAuthFailures, "ssh-max-auth-failures", 5, "failed logins per IP before it is blocked (0 disables)")
+	cmd.Flags().DurationVar(&sshAuthWindow, "ssh-auth-window", time.Minute, "window in which failed logins are counted")
+	cmd.Flags().DurationVar(&sshAuthCooldown, "ssh-auth-cooldown", 5*time.Minute, "how long a blocked IP is rejected")
+	cmd.Flags().BoolVar(&sshReadOnly, "ssh-read-only", false, "disable commands that modify the registry, even with --ssh-allow-toggle")
+	cmd.Flags().BoolVar(&sshAllowToggle, "ssh-allow-toggle", false, "enable the toggle command, which changes the registry (use with --ssh-auth other than demo)")
+	cmd.Flags().BoolVar(&sshRefreshRegistry, "ssh-refresh-registry", false, "re-read the registry file before list, status and health")
+	cmd.Flags().StringVar(&sshAuthBackend, "ssh-auth", "demo", "authentication backend (demo, password, authorized-keys, deny-all)")
+	cmd.Flags().StringVar(&sshAuthorizedKeys, "ssh-authorized-keys", "", "authorized_keys file for --ssh-auth authorized-keys (default ~/.ssh/authorized_keys)")
+	cmd.Flags().BoolVar(&sshCheckOnly, "check", false, "validate the SSH configuration and exit without listening")
+
+	return cmd
+}
+
+// Audit command
+func newAuditCmd() *cobra.Command {
+	var query auditQuery
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the local audit log",
+		Long: `Show events from the audit log written by the SSH server, newest last.
+
+--action matches the event action (session_start, command, auth_failed, ...)
+and, for command events, the command name, so --action toggle lists toggles.
+--action and --server may be repeated.`,
+		Example: `  devgen audit --since 1h --action toggle
+  devgen audit --server context7 --limit 20 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since != "" {
+				age, err := parseAge(since)
+				if err != nil {
+					return withExitCode(exitUsage, err)
+				}
+				query.since = age
+			}
+			if query.limit < 0 {
+				return usageError("--limit must not be negative")
+			}
+			if query.path == "" {
+				query.path = auditLogPath()
+			}
+			return queryAuditLog(query)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only show events newer than this (e.g. 30m, 1h, 7d)")
+	cmd.Flags().StringSliceVar(&query.actions, "action", nil, "only show this action or command (repeatable)")
+	cmd.Flags().StringSliceVar(&query.servers, "server", nil, "only show events for this server (repeatable)")
+	cmd.Flags().IntVar(&query.limit, "limit", 0, "show at most this many of the most recent events (0 for all)")
+	cmd.Flags().StringVar(&query.path, "file", "", "audit log to read (default audit.jsonl in the config directory)")
+	cmd.Flags().BoolVar(&query.asJSON, "json", false, "output as JSON")
 
 	return cmd
 }
@@ -309,15 +568,21 @@ CORE COMMANDS:
    Features:
    • Real-time server status monitoring
    • Interactive server toggling (↑/↓ to navigate, Enter to toggle)
-   • Category-based server organization with emoji indicators
+   • Servers grouped by category with collapsible sections
    • Live server statistics and health monitoring
    
    Usage:
      devgen dashboard
+     devgen dashboard --category database --status inactive
    
    Controls:
      ↑/↓ or j/k    Navigate server list
-     Enter/Space   Toggle selected server on/off
+     Enter/Space   Toggle selected server on/off (or fold a category header)
+     c             Collapse/expand the current category group
+     m             Mark/unmark the server for a bulk action
+     a / d         Activate / deactivate all marked servers (one save)
+     u             Clear all marks
+     s / S         Save a markdown / JSON snapshot of the list
      q             Quit dashboard
 
 🔌 devgen registry
@@ -328,6 +593,9 @@ CORE COMMANDS:
      servers    List all servers registered with the HTTP registry
      tools      Show all available tools across registered servers
      start      Start the MCP Registry server
+     prune      Remove dead servers from the registry file
+     import     Merge servers from another registry file
+     which      Show which servers expose a tool
    
    Features:
    • Centralized server discovery and management
@@ -336,10 +604,49 @@ CORE COMMANDS:
    • HTTP API for integration with other tools
    
    Usage:
-     devgen registry status
-     devgen registry servers
+     devgen registry status --json
+     devgen registry servers --format wide
+     devgen registry servers --status inactive --json
+     devgen registry servers --category database --status inactive
+     devgen registry servers --template '{{ .Name }} {{ .Status }}'
+     devgen registry categories
+     devgen registry stats --by-framework
      devgen registry tools
      devgen registry start
+     devgen registry prune --max-fails 5 --older-than 7d --dry-run
+     devgen registry import team.json --strategy merge
+     devgen registry rename old-name new-name
+     devgen registry validate            # exit 5 on problems
+     devgen registry check-env --strict  # exit 5 if active servers lack env vars
+     devgen registry diff team.json      # colored unified diff against --config
+     devgen registry export --format csv --with-health --concurrency 16
+     devgen registry which store_memory
+     devgen registry probe --endpoint stdio://./server.py
+     devgen registry health --json    # exit 0 healthy, 1 unhealthy, 2 bad registry
+     devgen registry health --follow --interval 30s
+
+🧰 devgen tool
+   Invoke tools on registered MCP servers
+   
+   Usage:
+     devgen tool call memory-mcp store_memory --args '{"content": "note"}'
+     devgen tool call github-mcp list_repos --json --timeout 1m
+     devgen tool history --limit 10
+     devgen tool replay 42
+
+🩺 devgen doctor
+   Diagnose common setup problems
+   
+   Usage:
+     devgen doctor                        # Show the diagnostic checklist
+     devgen doctor --clean                # Also remove stray debug files
+
+📜 devgen audit
+   Query the local audit log of SSH sessions and commands
+   
+   Usage:
+     devgen audit --since 1h --action toggle
+     devgen audit --server context7 --limit 20 --json
 
 🔐 devgen ssh
    Start SSH server for secure remote terminal access
@@ -349,14 +656,24 @@ CORE COMMANDS:
    • Essential for public-facing web deployments
    • Password and public key authentication
    • Interactive terminal sessions
+   • Audit log of sessions and commands (audit.jsonl in the config directory)
    
    Usage:
      devgen ssh                           # Start SSH server on default port 2222
      devgen ssh --ssh-port 2222          # Custom port
      devgen ssh --ssh-host 0.0.0.0       # Bind to all interfaces
+     devgen ssh --ssh-audit-logfire      # Mirror audit events to Logfire
+     devgen ssh --ssh-idle-timeout 5m    # Close idle sessions sooner
+     devgen ssh --ssh-allow-toggle --ssh-auth authorized-keys   # Let trusted keys toggle servers
+     devgen ssh --ssh-refresh-registry   # Always show current registry state
+     devgen ssh --ssh-auth authorized-keys --ssh-authorized-keys ~/.ssh/authorized_keys
+     DEVGEN_SSH_PASSWORD=... devgen ssh --ssh-auth password
+     devgen ssh --check                  # Readiness report, does not listen
    
    Connection:
      ssh -p 2222 demo@your-server.com    # Connect to SSH server
+     ssh -p 2222 demo@your-server.com list   # Run a single command and exit
+     ssh -p 2222 demo@your-server.com health --json   # Health summary as JSON
      Password: demo or devq
 
 PLANNED FEATURES (Coming Soon):
@@ -400,8 +717,29 @@ GLOBAL FLAGS:
   --log-level LEVEL       Set log level (debug, info, warn, error)
   --registry-url URL      MCP registry URL (default: http://127.0.0.1:31337)
   --use-registry          Use MCP registry for server management
+  -q, --quiet             Suppress progress lines, printing only errors and results
+  --no-emoji              Strip emoji from plain-text output
+  --no-color              Disable colored output (also set by NO_COLOR)
+  --redact PATTERN        Extra key or regex to mask in logs (tokens, *_KEY and
+                          bearer headers are always masked)
+  --config-dir DIR        Directory for audit log and dashboard state
+                          (default: $DEVGEN_CONFIG_DIR, ~/.devgen if it
+                          exists, else $XDG_CONFIG_HOME/devgen)
+  --allow-duplicate-endpoints
+                          Accept servers that share an endpoint
+  --timings               Print a per-phase timing breakdown to stderr
+                          (e.g. devgen registry health --timings)
   --version               Show version information
 
+EXIT CODES:
+───────────
+  0  success
+  1  runtime failure (or unhealthy servers for registry health)
+  2  registry file could not be read or parsed
+  3  usage error (bad flags or arguments)
+  4  named server or tool not found
+  5  input failed validation (e.g. registry import)
+
 CONFIGURATION:
 ──────────────
 DevGen automatically searches for configuration files in:
@@ -412,6 +750,11 @@ DevGen automatically searches for configuration files in:
 Custom configuration:
   devgen --config /path/to/custom.json dashboard
 
+Multiple registries (merged by server name, later files win):
+  devgen --config core.json,experimental.json dashboard
+  devgen --config 'registries/*.json' registry which store_memory
+  devgen --config 'registries/*.json' --save-to merged.json registry prune
+
 EXAMPLES:
 ─────────
 # Start the interactive dashboard
@@ -436,7 +779,7 @@ For more information and updates:
 Happy coding! 🚀
 `
 
-	fmt.Print(helpText)
+	printResult("%s", helpText)
 	return nil
 }
 
@@ -453,8 +796,20 @@ func newRegistryCmd() *cobra.Command {
 	cmd.AddCommand(
 		newRegistryStatusCmd(),
 		newRegistryServersCmd(),
+		newRegistryCategoriesCmd(),
+		newRegistryStatsCmd(),
+		newRegistryRenameCmd(),
+		newRegistryValidateCmd(),
+		newRegistryCheckEnvCmd(),
+		newRegistryDiffCmd(),
+		newRegistryExportCmd(),
 		newRegistryToolsCmd(),
 		newRegistryStartCmd(),
+		newRegistryPruneCmd(),
+		newRegistryImportCmd(),
+		newRegistryWhichCmd(),
+		newRegistryProbeCmd(),
+		newRegistryHealthCmd(),
 	)
 
 	return cmd
@@ -462,156 +817,836 @@ func newRegistryCmd() *cobra.Command {
 
 // Registry status command
 func newRegistryStatusCmd() *cobra.Command {
+	var asJSON bool
+
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Check MCP Registry status",
-		Long:  "Check the status of the MCP Registry and get basic information.",
+		Long: `Check the status of the MCP Registry and report its version, uptime,
+and server/tool counts. When the registry is down, the cause is reported as
+dns, timeout, connection refused, or http.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return checkRegistryStatus()
+			return checkRegistryStatus(asJSON)
 		},
 	}
 
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
 	return cmd
 }
 
 // Registry servers command
 func newRegistryServersCmd() *cobra.Command {
+	var opts serverListOptions
+
 	cmd := &cobra.Command{
 		Use:   "servers",
 		Short: "List servers from MCP Registry",
-		Long:  "List all registered servers from the HTTP MCP Registry.",
+		Long: `List all registered servers from the HTTP MCP Registry.
+
+Use --format wide to add endpoint, version, registration time and
+health check failure columns. --status and --category may be repeated
+to show servers in any of several states or categories; use
+"devgen registry categories" to see which categories exist.
+
+--template applies a Go template to each server, one per line, e.g.
+  devgen registry servers --template '{{ .Name }} {{ .Status }}'
+Fields match the --json keys: .Name .Description .URL .Port .Status
+.Category .Tools .LastSeen .Endpoint .Version .RegisteredAt .HealthCheckFails
+
+--sort orders the listing by name, status, tools or last-seen. Names and
+statuses sort A-Z, tools most first and last-seen most recent first;
+--reverse flips the order. Without --sort servers are listed in registry
+order. The dashboard's 'o' key cycles through the same orderings.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.format != "compact" && opts.format != "wide" {
+				return usageError("invalid --format value %q (expected compact or wide)", opts.format)
+			}
+			if opts.sortKey != "" {
+				if err := validateSortKey(opts.sortKey); err != nil {
+					return err
+				}
+			} else if opts.reverse {
+				return usageError("--reverse requires --sort")
+			}
+			if opts.template != "" && opts.asJSON {
+				return usageError("--template and --json cannot be used together")
+			}
+			return listRegistryServers(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", "compact", "output format (compact, wide)")
+	cmd.Flags().StringSliceVar(&opts.statuses, "status", nil, "only show servers with this status (repeatable, e.g. active, inactive, production-ready); active also matches production-ready and running")
+	cmd.Flags().StringSliceVar(&opts.categories, "category", nil, "only show servers in this category (repeatable)")
+	cmd.Flags().BoolVar(&opts.asJSON, "json", false, "output as JSON")
+	cmd.Flags().StringVar(&opts.template, "template", "", "Go template applied to each server (e.g. '{{ .Name }} {{ .Status }}')")
+	cmd.Flags().StringVar(&opts.sortKey, "sort", "", "sort by name, status, tools or last-seen")
+	cmd.Flags().BoolVar(&opts.reverse, "reverse", false, "reverse the --sort order")
+
+	return cmd
+}
+
+// Registry categories command
+func newRegistryCategoriesCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "categories",
+		Short: "List server categories with counts",
+		Long:  "List every category in the registry file with the number of servers, and active servers, in each.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listRegistryCategories(asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// Registry stats command
+func newRegistryStatsCmd() *cobra.Command {
+	var byFramework, asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize servers and tools in the registry",
+		Long:  "Show server, active server and tool totals for the registry file. With --by-framework, break them down by metadata.framework.",
+		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listRegistryServers()
+			return showRegistryStats(byFramework, asJSON)
 		},
 	}
 
+	cmd.Flags().BoolVar(&byFramework, "by-framework", false, "group counts by server framework")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
 	return cmd
 }
 
 // Registry tools command
 func newRegistryToolsCmd() *cobra.Command {
+	var opts toolListOptions
+	var export, output string
+	var unused bool
+	var since string
+	var errorRatio float64
+
 	cmd := &cobra.Command{
 		Use:   "tools",
 		Short: "List tools from MCP Registry",
-		Long:  "List all available tools from the HTTP MCP Registry.",
+		Long: `List all available tools from the HTTP MCP Registry.
+
+--search keeps tools whose name or description contains the text, and
+--server (repeatable) keeps tools of the given servers. --limit and
+--offset page through the matches, e.g.
+  devgen registry tools --server github-mcp --limit 20 --offset 20
+--json includes the total match count and the paging used.
+
+--template applies a Go template to each tool, one per line, e.g.
+  devgen registry tools --template '{{ .Name }}: {{ .Description }}'
+
+--export markdown instead generates a tool reference from the local
+registry file, grouped by server, for committing into a repo's docs:
+  devgen registry tools --export markdown --output docs/tools.md
+With --export, --template replaces the whole document layout; pass
+@path to read it from a file. The template gets .Source, .Generated,
+.ToolCount and .Servers, where each server has .Name .Description
+.Category .Framework .Endpoint .Status and .Tools (.Name .Description
+.UseCount .ErrorCount .LastUsed). The cell function escapes a value for
+a markdown table.
+
+--unused reports tools in the local registry that have never been used or,
+with --since, not used within that time, along with tools that failed at
+least --error-ratio of their calls (after 5 or more calls). Only tools in
+the registry's tool index are tracked:
+  devgen registry tools --unused --since 30d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listRegistryTools()
+			if unused {
+				if export != "" || opts.template != "" || opts.search != "" || opts.limit != 0 || opts.offset != 0 {
+					return usageError("--unused cannot be combined with --export, --template, --search, --limit or --offset")
+				}
+				if errorRatio < 0 || errorRatio > 1 {
+					return usageError("--error-ratio must be between 0 and 1")
+				}
+				usage := toolUsageOptions{errorRatio: errorRatio, servers: opts.servers, asJSON: opts.asJSON}
+				if since != "" {
+					age, err := parseAge(since)
+					if err != nil {
+						return withExitCode(exitUsage, err)
+					}
+					usage.since = age
+				}
+				return listUnusedTools(usage)
+			}
+			if since != "" {
+				return usageError("--since requires --unused")
+			}
+			if export != "" {
+				return exportToolDocs(export, opts.template, output)
+			}
+			if output != "" {
+				return usageError("--output requires --export")
+			}
+			if opts.limit < 0 || opts.offset < 0 {
+				return usageError("--limit and --offset must not be negative")
+			}
+			if opts.template != "" && opts.asJSON {
+				return usageError("--template and --json cannot be used together")
+			}
+			return listRegistryTools(opts)
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.template, "template", "", "Go template applied to each tool (fields .Name and .Description), or the document template with --export")
+	cmd.Flags().StringVar(&opts.search, "search", "", "only show tools whose name or description contains this text")
+	cmd.Flags().StringSliceVar(&opts.servers, "server", nil, "only show tools of this server (repeatable)")
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "show at most this many tools (0 for all)")
+	cmd.Flags().IntVar(&opts.offset, "offset", 0, "skip this many matching tools")
+	cmd.Flags().BoolVar(&opts.asJSON, "json", false, "output as JSON with paging metadata")
+	cmd.Flags().StringVar(&export, "export", "", "generate tool documentation from the local registry (markdown)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write the --export document to this file instead of stdout")
+	cmd.Flags().BoolVar(&unused, "unused", false, "list tools that are unused or fail often, from the local registry")
+	cmd.Flags().StringVar(&since, "since", "", "with --unused, also list tools not used within this time (e.g. 30d, 12h)")
+	cmd.Flags().Float64Var(&errorRatio, "error-ratio", 0.5, "with --unused, list tools failing at least this share of calls (0 to disable)")
+
 	return cmd
 }
 
 // Registry start command
 func newRegistryStartCmd() *cobra.Command {
+	var wait bool
+	var waitTimeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start the MCP Registry",
-		Long:  "Start the HTTP-based MCP Registry server.",
+		Long: `Start the HTTP-based MCP Registry server.
+
+With --wait the command blocks until the registry answers its health
+endpoint, so scripts can safely run "registry status" or "registry servers"
+next. It fails if the registry does not respond within --wait-timeout.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return startMCPRegistry()
+			if waitTimeout <= 0 {
+				return usageError("--wait-timeout must be positive")
+			}
+			return startMCPRegistry(wait, waitTimeout)
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "block until the registry responds to a health probe")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 30*time.Second, "how long --wait waits for the registry")
+
 	return cmd
 }
 
-// Load MCP registry
-func loadMCPRegistry() (*MCPRegistry, error) {
-	// Try multiple locations for the config file
-	var data []byte
-	var err error
-
-	// First try the specified config file
-	data, err = ioutil.ReadFile(configFile)
-	if err != nil && configFile == "mcp_status.json" {
-		// Smart discovery of machina repository
-		machinaRoot := findMachinaRoot()
+// Registry prune command
+func newRegistryPruneCmd() *cobra.Command {
+	var maxFails int
+	var olderThan string
+	var dryRun bool
+	var yes bool
 
-		locations := []string{
-			"./mcp_status.json",
-			"../mcp_status.json",
-		}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove dead servers from the registry file",
+		Long:  "Remove servers whose health checks keep failing and that have not been seen recently.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			age, err := parseAge(olderThan)
+			if err != nil {
+				return withExitCode(exitUsage, err)
+			}
+			return pruneRegistryServers(maxFails, age, dryRun, yes)
+		},
+	}
 
-		if machinaRoot != "" {
-			locations = append(locations, filepath.Join(machinaRoot, "mcp_status.json"))
-		}
+	cmd.Flags().IntVar(&maxFails, "max-fails", 5, "prune servers with more than this many consecutive health check failures")
+	cmd.Flags().StringVar(&olderThan, "older-than", "7d", "only prune servers not seen within this window (e.g. 12h, 7d)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be pruned without changing the registry")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip the confirmation prompt")
 
-		// Fallback locations
-		locations = append(locations,
-			"/Users/dionedge/devqai/machina/mcp_status.json",
-			os.ExpandEnv("$HOME/devqai/machina/mcp_status.json"),
-		)
+	return cmd
+}
 
-		for _, location := range locations {
-			data, err = ioutil.ReadFile(location)
-			if err == nil {
-				configFile = location
-				break
+// Registry validate command
+func newRegistryValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a registry file for problems",
+		Long: `Check the registry file (or the given file) for missing names and endpoints,
+duplicate server names and servers that share an endpoint. Shared endpoints are
+usually a copy-paste mistake; pass --allow-duplicate-endpoints when they are
+intentional. Exits 5 when problems are found.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
 			}
-		}
+			return validateRegistryCommand(path)
+		},
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to read registry file: %v", err)
-	}
+	return cmd
+}
 
-	var registry MCPRegistry
-	if err := json.Unmarshal(data, &registry); err != nil {
-		return nil, fmt.Errorf("failed to parse registry JSON: %v", err)
+// Registry check-env command
+func newRegistryCheckEnvCmd() *cobra.Command {
+	var strict, includeAll, asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "check-env",
+		Short: "Report environment variables that active servers need but are not set",
+		Long: `Check the metadata.environment_vars of every active server (or every
+server with --all) against the current environment. Variables that are
+unset or empty are reported as missing.
+
+Use it as a pre-flight check in CI or before a deploy: with --strict the
+command exits 5 when anything is missing.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return checkRegistryEnv(strict, includeAll, asJSON)
+		},
 	}
 
-	return &registry, nil
+	cmd.Flags().BoolVar(&strict, "strict", false, "exit non-zero when any required variable is missing")
+	cmd.Flags().BoolVar(&includeAll, "all", false, "also check inactive servers")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output missing variables per server as JSON")
+
+	return cmd
 }
 
-// Save MCP registry to file
-func saveMCPRegistry(registry *MCPRegistry) error {
-	// Debug: log save attempt
-	logFile, _ := os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	fmt.Fprintf(logFile, "SAVE: Attempting to save registry to %s\n", configFile)
-	
-	// Find and log the crawl4ai-mcp status being saved
-	for _, server := range registry.Servers {
-		if server.Name == "crawl4ai-mcp" {
-			fmt.Fprintf(logFile, "SAVE: crawl4ai-mcp status being written: %s\n", server.Status)
-			break
-		}
-	}
-	logFile.Close()
-	
-	data, err := json.MarshalIndent(registry, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal registry JSON: %v", err)
-	}
+// Registry diff command
+func newRegistryDiffCmd() *cobra.Command {
+	var exitCode bool
 
-	// Write to file and ensure it's synced
-	file, err := os.OpenFile(configFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		logFile, _ := os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		fmt.Fprintf(logFile, "SAVE ERROR: Failed to open file: %v\n", err)
-		logFile.Close()
-		return fmt.Errorf("failed to open registry file: %v", err)
+	cmd := &cobra.Command{
+		Use:   "diff <file> [file]",
+		Short: "Show differences between two registry files",
+		Long: `Show a unified diff from the registry (--config) to the given file, or from
+the first file to the second. Both sides are parsed and re-encoded before
+comparing, so only real changes show up, not formatting or key order.
+
+On a terminal additions are green and removals red; the output is plain
+when piped or with --no-color. With --exit-code the command exits 1 when
+the registries differ, like git diff.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			oldPath, newPath := "", args[0]
+			if len(args) == 2 {
+				oldPath, newPath = args[0], args[1]
+			}
+			return diffRegistryFiles(oldPath, newPath, exitCode)
+		},
 	}
-	defer file.Close()
 
-	if _, err := file.Write(data); err != nil {
-		logFile, _ := os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		fmt.Fprintf(logFile, "SAVE ERROR: Failed to write data: %v\n", err)
-		logFile.Close()
-		return fmt.Errorf("failed to write registry data: %v", err)
-	}
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "exit 1 when the registries differ")
 
-	// Force sync to disk
-	if err := file.Sync(); err != nil {
-		logFile, _ := os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		fmt.Fprintf(logFile, "SAVE ERROR: Failed to sync: %v\n", err)
-		logFile.Close()
-		return fmt.Errorf("failed to sync registry file: %v", err)
-	}
+	return cmd
+}
 
-	logFile, _ = os.OpenFile("key_debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	fmt.Fprintf(logFile, "SAVE SUCCESS: Registry saved\n")
-	logFile.Close()
+// Registry export command
+func newRegistryExportCmd() *cobra.Command {
+	opts := exportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the registry as JSON or CSV",
+		Long: `Write the registry file as JSON (importable with "registry import") or CSV.
+Servers are streamed one at a time, so large registries do not have to be held
+twice in memory. --with-health probes every server (up to --concurrency at once)
+and adds the live result to each entry without changing the registry.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportRegistry(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", "json", "output format (json, csv)")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "write to this file instead of stdout")
+	cmd.Flags().BoolVar(&opts.withHealth, "with-health", false, "probe each server and include its live health")
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", 8, "maximum concurrent probes with --with-health")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Second, "maximum time to wait for each probe")
+
+	return cmd
+}
+
+// Registry rename command
+func newRegistryRenameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a server and update its tools",
+		Long:  "Rename a server in the registry file and point every tool that referenced the old name at the new one. Refuses if a server with the new name already exists.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renameRegistryServer(args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+// Registry import command
+func newRegistryImportCmd() *cobra.Command {
+	var strategy string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Merge servers from another registry file",
+		Long:  "Import servers from another registry file into the current registry. On name conflicts, 'skip' keeps the existing server, 'overwrite' replaces it and 'merge' unions tools and keeps the newer last-seen time.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importRegistry(args[0], strategy)
+		},
+	}
+
+	cmd.Flags().StringVar(&strategy, "strategy", "skip", "conflict strategy: skip, overwrite or merge")
+
+	return cmd
+}
+
+// Registry which command
+func newRegistryWhichCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "which <tool>",
+		Short: "Show which servers expose a tool",
+		Long:  "Look up a tool name and list every registered server that provides it, with its status.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return whichTool(args[0], asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// Doctor command
+func newDoctorCmd() *cobra.Command {
+	var clean bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose common setup problems",
+		Long: `Check that the registry file is readable and valid, python3 is available
+for Logfire, the Logfire token is set and the SSH port is free, and report
+debug files left in the working directory by earlier versions. Use --clean
+to remove those files.
+
+Each check prints pass, warn or fail; the command exits non-zero if any fail.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(clean)
+		},
+	}
+
+	cmd.Flags().BoolVar(&clean, "clean", false, "remove stray debug files")
+
+	return cmd
+}
+
+// Tool command
+func newToolCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tool",
+		Short: "Invoke tools on MCP servers",
+		Long:  "Call tools exposed by registered MCP servers over stdio or HTTP.",
+	}
+
+	cmd.AddCommand(
+		newToolCallCmd(),
+		newToolHistoryCmd(),
+		newToolReplayCmd(),
+	)
+
+	return cmd
+}
+
+func newToolCallCmd() *cobra.Command {
+	var (
+		rawArgs string
+		timeout time.Duration
+		asJSON  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "call <server> <tool>",
+		Short: "Call a tool on an MCP server",
+		Long: `Connect to a registered MCP server using its endpoint (stdio:// starts the
+server as a child process, http(s):// posts to it), invoke the named tool with
+the given JSON arguments, and print the result. Usage counters for the tool are
+updated in the registry.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return invokeTool(args[0], args[1], rawArgs, timeout, asJSON)
+		},
+	}
+
+	cmd.Flags().StringVar(&rawArgs, "args", "", "tool arguments as a JSON object")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "maximum time to wait for the tool")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the raw JSON-RPC result")
+
+	return cmd
+}
+
+func newToolHistoryCmd() *cobra.Command {
+	var (
+		servers []string
+		limit   int
+		asJSON  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List recorded tool calls",
+		Long: `List tool calls made with "devgen tool call", newest last. Arguments and
+results are stored with secrets masked in tool_history.jsonl in the config directory.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showToolHistory(servers, limit, asJSON)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&servers, "server", nil, "only show calls to this server (repeatable)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "show at most this many of the most recent calls (0 for all)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func newToolReplayCmd() *cobra.Command {
+	var (
+		rawArgs string
+		timeout time.Duration
+		asJSON  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay <id>",
+		Short: "Re-run a recorded tool call",
+		Long: `Call the same server and tool again with the arguments recorded in the
+history. If the arguments contained secrets they were masked when recorded, so
+pass them again with --args.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return usageError("invalid call id %q", args[0])
+			}
+			return replayToolCall(id, rawArgs, timeout, asJSON)
+		},
+	}
+
+	cmd.Flags().StringVar(&rawArgs, "args", "", "replace the recorded arguments with this JSON object")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "maximum time to wait for the tool")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the raw JSON-RPC result")
+
+	return cmd
+}
+
+// Registry probe command
+func newRegistryProbeCmd() *cobra.Command {
+	var (
+		endpoint string
+		timeout  time.Duration
+		asJSON   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "probe [server]",
+		Short: "Test connectivity to an MCP server endpoint",
+		Long: `Run the connectivity check against a registered server, or against any
+endpoint given with --endpoint without editing the registry. Supported
+schemes: stdio://, http://, https://, ws://, wss://.
+
+Probing a registered server records the result in the registry: success
+updates last_seen and resets health_check_failures, failure increments it.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 0) == (endpoint == "") {
+				return usageError("specify either a server name or --endpoint")
+			}
+			server := ""
+			if len(args) == 1 {
+				server = args[0]
+			}
+			return probeEndpointCommand(server, endpoint, timeout, asJSON)
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "endpoint to probe instead of a registered server")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "maximum time to wait for the endpoint")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// Registry health command
+func newRegistryHealthCmd() *cobra.Command {
+	var (
+		opts     healthPassOptions
+		asJSON   bool
+		follow   bool
+		interval time.Duration
+		daemon   healthDaemonOptions
+		asDaemon bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Probe registered servers and report their health",
+		Long: `Probe the endpoint of every active server (or every server with --all),
+record the results in the registry, and report them.
+
+A server can declare its own check in metadata.health_check: a URL
+(http, https, ws, wss) is probed instead of the endpoint, and
+"cmd:<command>" runs the command with --timeout, treating exit 0 as
+healthy. The command gets DEVGEN_SERVER_NAME and DEVGEN_SERVER_ENDPOINT
+in its environment. Other values are ignored and the endpoint is probed.
+
+With --follow the checks repeat every --interval and the table is redrawn in
+place, marking servers that went down or recovered since the previous pass.
+Press Ctrl+C to stop. With --json each pass is printed as one JSON line.
+
+With --daemon the checks repeat every --interval without any table, logging
+servers that go down or recover. It writes a PID file (default health.pid in
+the config directory) and stops cleanly on SIGTERM or SIGINT. --listen serves
+the latest pass as JSON at /health, answering 503 while any server is
+unhealthy, and --logfire also sends each pass and change to Logfire.
+
+Probes run concurrently; --parallel caps how many run at once. On a
+terminal a progress bar on stderr counts servers as they are checked.
+
+Exit codes:
+  0  all checked servers are healthy
+  1  one or more servers are unhealthy
+  2  the registry file could not be read or parsed
+  3  usage error (bad flags or arguments)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Unhealthy servers are a result, not a usage mistake
+			cmd.SilenceUsage = true
+			if opts.parallel < 0 {
+				return usageError("--parallel must not be negative")
+			}
+			if asDaemon {
+				if follow || asJSON {
+					return usageError("--daemon cannot be combined with --follow or --json")
+				}
+				daemon.interval = interval
+				return runHealthDaemon(opts, daemon)
+			}
+			if daemon.listen != "" || daemon.pidFile != "" || daemon.logfire {
+				return usageError("--listen, --pid-file and --logfire require --daemon")
+			}
+			if follow {
+				return followRegistryHealth(opts, interval, asJSON)
+			}
+			return checkRegistryHealth(opts, asJSON)
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Second, "maximum time to wait for each server")
+	cmd.Flags().BoolVar(&opts.includeAll, "all", false, "also probe inactive servers")
+	cmd.Flags().IntVar(&opts.parallel, "parallel", 0, "maximum probes to run at once (0 for all servers at once)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&follow, "follow", false, "keep checking and redraw the table until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "time between passes with --follow or --daemon")
+	cmd.Flags().BoolVar(&asDaemon, "daemon", false, "keep checking in the background without a TUI until SIGTERM")
+	cmd.Flags().StringVar(&daemon.listen, "listen", "", "with --daemon, serve the latest results at /health on this address (e.g. 127.0.0.1:8089)")
+	cmd.Flags().StringVar(&daemon.pidFile, "pid-file", "", "with --daemon, PID file to write (default health.pid in the config directory)")
+	cmd.Flags().BoolVar(&daemon.logfire, "logfire", false, "with --daemon, also send results and changes to Logfire")
+
+	return cmd
+}
+
+// readMCPRegistry reads and parses the registry from disk, bypassing the cache
+func readMCPRegistry() (*MCPRegistry, error) {
+	defer startTiming("registry load")()
+
+	// Several files (comma-separated or a glob) are merged into one registry
+	if isMultiRegistryConfig(configFile) {
+		return loadMergedRegistry()
+	}
+
+	// Try multiple locations for the config file
+	var data []byte
+	var err error
+
+	// First try the specified config file
+	data, err = ioutil.ReadFile(configFile)
+	if err != nil && configFile == "mcp_status.json" {
+		// Smart discovery of machina repository
+		machinaRoot := findMachinaRoot()
+
+		locations := []string{
+			"./mcp_status.json",
+			"../mcp_status.json",
+		}
+
+		if machinaRoot != "" {
+			locations = append(locations, filepath.Join(machinaRoot, "mcp_status.json"))
+		}
+
+		// Fallback locations
+		locations = append(locations,
+			"/Users/dionedge/devqai/machina/mcp_status.json",
+			os.ExpandEnv("$HOME/devqai/machina/mcp_status.json"),
+		)
+
+		for _, location := range locations {
+			data, err = ioutil.ReadFile(location)
+			if err == nil {
+				configFile = location
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %v", err)
+	}
+
+	return decodeRegistryFile(configFile, data)
+}
+
+// decodeRegistryFile parses one registry file, upgrading an older schema
+// version and writing the upgraded file back after a backup of the original.
+// When the file cannot be written (e.g. a read-only checkout) the upgrade is
+// kept in memory and the write-back is left to the next save.
+func decodeRegistryFile(path string, data []byte) (*MCPRegistry, error) {
+	// Upgrade older schema versions before decoding so no fields are lost
+	migrated, fromVersion, changed, err := migrateRegistryData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var registry MCPRegistry
+	if err := json.Unmarshal(migrated, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse registry JSON: %v", err)
+	}
+	normalizeMCPRegistry(&registry)
+
+	if changed {
+		rememberMigration(path, data, fromVersion)
+		if err := writeRegistryFile(path, &registry); err != nil {
+			log.Warn("Could not write migrated registry back; it will be upgraded on the next save", "file", path, "error", err)
+		}
+	}
+
+	return &registry, nil
+}
+
+// emptyRegistryMessage explains a registry file that parsed but lists no servers
+const emptyRegistryMessage = "registry loaded but contains 0 servers"
+
+// normalizeMCPRegistry replaces null or missing lists with empty ones so a
+// degenerate file ({} or "servers": null) behaves exactly like an empty registry
+func normalizeMCPRegistry(registry *MCPRegistry) {
+	if registry.Servers == nil {
+		registry.Servers = []MCPServer{}
+	}
+	if registry.Tools == nil {
+		registry.Tools = []MCPTool{}
+	}
+	for i := range registry.Servers {
+		if registry.Servers[i].Tools == nil {
+			registry.Servers[i].Tools = []string{}
+		}
+	}
+}
+
+// Save MCP registry to file
+func saveMCPRegistry(registry *MCPRegistry) error {
+	defer startTiming("registry save")()
+
+	var err error
+	if isMultiRegistryConfig(configFile) {
+		err = saveMergedRegistry(registry)
+	} else {
+		err = writeRegistryFile(configFile, registry)
+	}
+	cacheSavedRegistry(registry, err)
+	return err
+}
+
+// writeRegistryFile writes a registry to a single file and syncs it to disk
+func writeRegistryFile(path string, registry *MCPRegistry) error {
+	// Debug: log save attempt
+	logFile, _ := openDebugLog("key_debug.log")
+	fmt.Fprintf(logFile, "SAVE: Attempting to save registry to %s\n", path)
+	
+	// Find and log the crawl4ai-mcp status being saved
+	for _, server := range registry.Servers {
+		if server.Name == "crawl4ai-mcp" {
+			fmt.Fprintf(logFile, "SAVE: crawl4ai-mcp status being written: %s\n", server.Status)
+			break
+		}
+	}
+	logFile.Close()
+	
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry JSON: %v", err)
+	}
+
+	// The first write of a migrated file keeps a copy of the old schema
+	if err := backupMigratedFile(path); err != nil {
+		return err
+	}
+
+	// Write to a temporary file in the same directory, sync it, then rename it
+	// over the registry so readers never see a partially written file
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		logFile, _ := openDebugLog("key_debug.log")
+		fmt.Fprintf(logFile, "SAVE ERROR: Failed to open file: %v\n", err)
+		logFile.Close()
+		return fmt.Errorf("failed to open registry file: %v", err)
+	}
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath) // no-op once the rename succeeds
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		logFile, _ := openDebugLog("key_debug.log")
+		fmt.Fprintf(logFile, "SAVE ERROR: Failed to write data: %v\n", err)
+		logFile.Close()
+		return fmt.Errorf("failed to write registry data: %v", err)
+	}
+
+	// Force sync to disk
+	if err := file.Sync(); err != nil {
+		logFile, _ := openDebugLog("key_debug.log")
+		fmt.Fprintf(logFile, "SAVE ERROR: Failed to sync: %v\n", err)
+		logFile.Close()
+		return fmt.Errorf("failed to sync registry file: %v", err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := file.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set registry file mode: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close registry file: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace registry file: %v", err)
+	}
+
+	logFile, _ = openDebugLog("key_debug.log")
+	fmt.Fprintf(logFile, "SAVE SUCCESS: Registry saved\n")
+	logFile.Close()
 
 	return nil
 }
@@ -647,6 +1682,43 @@ func findMachinaRoot() string {
 	return ""
 }
 
+// devgenDir returns the per-user devgen config and state directory. It is the
+// single source of truth for the audit log, dashboard state and similar files:
+// --config-dir, then $DEVGEN_CONFIG_DIR, then an existing ~/.devgen, then
+// $XDG_CONFIG_HOME/devgen, and finally ~/.devgen.
+func devgenDir() string {
+	if configDir != "" {
+		return configDir
+	}
+	if dir := os.Getenv("DEVGEN_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".devgen"
+	}
+	legacy := filepath.Join(home, ".devgen")
+	// Keep using ~/.devgen when it already holds state from earlier versions
+	if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+		return legacy
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "devgen")
+	}
+	return legacy
+}
+
+// openDebugLog opens a debug log under the config directory for appending, so
+// debug output no longer lands in whatever directory devgen was run from
+func openDebugLog(name string) (*os.File, error) {
+	path := filepath.Join(devgenDir(), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
 // Load environment variables
 func loadEnvFile() {
 	// Look for .env file in current directory or parent directories
@@ -679,7 +1751,7 @@ func loadEnvFile() {
 				}
 			}
 
-			fmt.Printf("📄 Loaded environment variables from: %s\n", envPath)
+			loadedEnvFile = envPath
 			return
 		}
 
@@ -694,12 +1766,42 @@ func loadEnvFile() {
 // Dashboard implementation
 // Dashboard methods moved to dashboard.go
 
-// testMCPServerConnectivity tests if an MCP server can actually start
+// testMCPServerConnectivity tests if an MCP server can actually start, using the
+// server's own health check when metadata.health_check declares one
 func testMCPServerConnectivity(server *MCPServer) bool {
-	// For stdio-based servers, try to actually start them briefly
-	if strings.HasPrefix(server.Endpoint, "stdio://") {
+	return probeServer(*server, 5*time.Second) == nil
+}
+
+// supportedEndpointSchemes are the transports devgen knows how to probe
+var supportedEndpointSchemes = []string{"stdio", "http", "https", "ws", "wss"}
+
+// endpointScheme returns the scheme of an endpoint, or an error if devgen can't probe it
+func endpointScheme(endpoint string) (string, error) {
+	scheme, _, found := strings.Cut(endpoint, "://")
+	if !found || scheme == "" {
+		return "", fmt.Errorf("endpoint %q has no scheme (expected one of %s)", endpoint, strings.Join(supportedEndpointSchemes, ", "))
+	}
+	for _, supported := range supportedEndpointSchemes {
+		if scheme == supported {
+			return scheme, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported endpoint scheme %q (expected one of %s)", scheme, strings.Join(supportedEndpointSchemes, ", "))
+}
+
+// probeMCPEndpoint checks that an endpoint is usable: stdio scripts must exist,
+// HTTP endpoints must answer and WebSocket endpoints must accept an upgrade.
+// It returns nil when the endpoint is reachable.
+func probeMCPEndpoint(endpoint string, timeout time.Duration) error {
+	scheme, err := endpointScheme(endpoint)
+	if err != nil {
+		return err
+	}
+
+	switch scheme {
+	case "stdio":
 		// Extract the Python script path from the endpoint
-		scriptPath := strings.TrimPrefix(server.Endpoint, "stdio://")
+		scriptPath := strings.TrimPrefix(endpoint, "stdio://")
 
 		// Update path to actual location
 		if strings.Contains(scriptPath, "context7-mcp") {
@@ -708,37 +1810,62 @@ func testMCPServerConnectivity(server *MCPServer) bool {
 			scriptPath = "/Users/dionedge/devqai/machina/mcp-servers/memory_mcp.py"
 		}
 
-		// Simple connectivity test - check if file exists and is executable
-		if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-			return false
+		// Simple connectivity test - check if the script exists
+		argv := resolveStdioCommand(scriptPath)
+		if _, err := os.Stat(argv[len(argv)-1]); err != nil {
+			return fmt.Errorf("server script not found: %s", scriptPath)
 		}
-		return true
-	}
+		return nil
+
+	case "ws", "wss":
+		return probeWebSocket(endpoint, timeout)
 
-	// For other protocols, assume they're working
-	return true
+	default:
+		// Any HTTP response means something is listening
+		client := &http.Client{Timeout: timeout}
+		resp, err := client.Get(endpoint)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
 }
 
 // toggleServer toggles the status of an MCP server
 func toggleServer(serverName string) error {
 	registry, err := loadMCPRegistry()
 	if err != nil {
-		return fmt.Errorf("failed to load registry: %v", err)
+		return registryLoadError(err)
 	}
 
+	var oldStatus, newStatus string
+	found := false
 	for i := range registry.Servers {
 		if registry.Servers[i].Name == serverName {
+			oldStatus = registry.Servers[i].Status
 			if registry.Servers[i].Status == "active" || registry.Servers[i].Status == "production-ready" || registry.Servers[i].Status == "running" {
 				registry.Servers[i].Status = "inactive"
 			} else {
 				registry.Servers[i].Status = "active"
 			}
+			newStatus = registry.Servers[i].Status
+			found = true
 			break
 		}
 	}
+	if !found {
+		// Nothing changed, so leave the file alone
+		return notFoundError("server not found: %s", serverName)
+	}
 
 	// Save the updated registry back to file
-	return saveMCPRegistry(registry)
+	if err := saveMCPRegistry(registry); err != nil {
+		return err
+	}
+
+	logRegistryEvent("toggle", serverName, oldStatus, newStatus)
+	return nil
 }
 
 
@@ -752,34 +1879,75 @@ func toggleServer(serverName string) error {
 
 
 
+// Location of the SSH host key, relative to the working directory
+const (
+	sshKeyDir      = ".ssh"
+	sshHostKeyName = "devgen_host_key"
+)
+
 // SSH Server implementation
 func startSSHServer() error {
 	registry, err := loadMCPRegistry()
 	if err != nil {
-		return fmt.Errorf("failed to load MCP registry: %w", err)
+		return withExitCode(exitRegistryError, fmt.Errorf("failed to load MCP registry: %w", err))
 	}
 
 	// Ensure SSH directory exists
-	sshDir := ".ssh"
+	sshDir := sshKeyDir
 	if err := os.MkdirAll(sshDir, 0700); err != nil {
 		return fmt.Errorf("failed to create .ssh directory: %w", err)
 	}
 
 	// Generate host key if it doesn't exist
-	hostKeyPath := filepath.Join(sshDir, "devgen_host_key")
+	hostKeyPath := filepath.Join(sshDir, sshHostKeyName)
 	if err := generateHostKeyIfNotExists(hostKeyPath); err != nil {
 		return fmt.Errorf("failed to generate host key: %w", err)
 	}
 
+	auth, err := newAuthenticator(sshAuthBackend)
+	if err != nil {
+		return err
+	}
+	limiter := newAuthLimiter(sshMaxAuthFailures, sshAuthWindow, sshAuthCooldown)
+
 	// Create SSH server with Wish middleware
 	s, err := wish.NewServer(
 		wish.WithAddress(fmt.Sprintf("%s:%d", sshHost, sshPort)),
 		wish.WithHostKeyPath(hostKeyPath),
 		wish.WithPasswordAuth(func(ctx ssh.Context, password string) bool {
-			return password == "demo" || password == "devq"
+			ip := remoteIP(ctx.RemoteAddr())
+			if !limiter.allowed(ip) {
+				auditSSHAuth(ctx, "auth_rejected")
+				return false
+			}
+			ctx.SetValue(sshAuthMethodKey, "password")
+			// A key rejected earlier in this connection must not be credited to the session
+			ctx.SetValue(sshFingerprintKey, "")
+			if auth.AuthPassword(ctx, password) {
+				limiter.recordSuccess(ip)
+				return true
+			}
+			auditSSHAuth(ctx, "auth_failed")
+			if limiter.recordFailure(ip) {
+				auditSSHAuth(ctx, "auth_blocked")
+			}
+			return false
 		}),
 		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
-			return true
+			ip := remoteIP(ctx.RemoteAddr())
+			if !limiter.allowed(ip) {
+				auditSSHAuth(ctx, "auth_rejected")
+				return false
+			}
+			if auth.AuthPublicKey(ctx, key) {
+				// Only the key that authenticated is recorded against the session
+				ctx.SetValue(sshAuthMethodKey, "publickey")
+				ctx.SetValue(sshFingerprintKey, publicKeyFingerprint(key))
+				limiter.recordSuccess(ip)
+				return true
+			}
+			// Clients offer several keys in turn, so a rejected key is not counted as a failure
+			return false
 		}),
 		wish.WithMiddleware(
 			func(next ssh.Handler) ssh.Handler {
@@ -793,9 +1961,9 @@ func startSSHServer() error {
 		return fmt.Errorf("failed to create SSH server: %w", err)
 	}
 
-	fmt.Printf("SSH server started at %s:%d\n", sshHost, sshPort)
-	fmt.Printf("Connect with: ssh -p %d demo@%s\n", sshPort, sshHost)
-	fmt.Printf("Password: demo or devq\n")
+	printResult("SSH server started at %s:%d\n", sshHost, sshPort)
+	printResult("Connect with: ssh -p %d demo@%s\n", sshPort, sshHost)
+	printResult("Authentication: %s\n", auth.Describe())
 
 	return s.ListenAndServe()
 }
@@ -825,80 +1993,233 @@ func generateHostKeyIfNotExists(hostKeyPath string) error {
 		return fmt.Errorf("failed to encode private key: %w", err)
 	}
 
-	fmt.Printf("Generated SSH host key at %s\n", hostKeyPath)
+	printProgress("Generated SSH host key at %s\n", hostKeyPath)
 	return nil
 }
 
 func handleSSHSession(sess ssh.Session, registry *MCPRegistry) {
+	// Create terminal renderer
+	renderer := lipgloss.NewRenderer(sess)
+
+	// Non-interactive mode: `ssh host <command>` runs a single command and exits
+	if command := sess.Command(); len(command) > 0 {
+		auditSSHSession(sess, "session_start", "", "")
+		defer auditSSHSession(sess, "session_end", "", "")
+
+		_, _, err := runSSHCommand(sess, registry, command, renderer)
+		if err != nil {
+			sess.Exit(1)
+			return
+		}
+		sess.Exit(0)
+		return
+	}
+
 	pty, winCh, isPty := sess.Pty()
 	if !isPty {
-		fmt.Fprintf(sess, "DevGen CLI requires a PTY\n")
+		fmt.Fprintf(sess, "DevGen CLI requires a PTY (or pass a command, e.g. ssh host list)\n")
 		sess.Exit(1)
 		return
 	}
 
-	// Create terminal renderer
-	renderer := lipgloss.NewRenderer(sess)
-
-	// Style definitions for SSH terminal
-	titleStyle := renderer.NewStyle().
-		Foreground(lipgloss.Color("#FF10F0")).
-		Bold(true).
-		Padding(1, 2)
-
 	headerStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("#00FFFF")).
 		Bold(true)
 
-	// Welcome message
-	welcome := titleStyle.Render("🚀 DevGen SSH Terminal") + "\n\n" +
-		headerStyle.Render("Available Commands:") + "\n" +
-		"• list        - List all MCP servers\n" +
-		"• status <name> - Show server status\n" +
-		"• health      - Check health of all servers\n" +
-		"• help        - Show this help\n" +
-		"• exit        - Close connection\n\n"
+	fmt.Fprint(sess, sshWelcome(renderer))
 
-	fmt.Fprint(sess, welcome)
+	// The line editor echoes input and completes commands and server names on
+	// tab. It reads the session's current registry, so a reload is picked up.
+	terminal := term.NewTerminal(sess, headerStyle.Render("devgen> "))
+	if pty.Window.Width > 0 {
+		terminal.SetSize(pty.Window.Width, pty.Window.Height)
+	}
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, candidates := completeSSHLine(line, pos, registry)
+		if len(candidates) > 0 {
+			// The terminal is unlocked during the callback; Write redraws the prompt below
+			fmt.Fprintf(terminal, "%s\n", strings.Join(candidates, "  "))
+		}
+		return newLine, newPos, true
+	}
 
 	// Handle window size changes
 	go func() {
 		for win := range winCh {
 			pty.Window.Width = win.Width
 			pty.Window.Height = win.Height
+			if win.Width > 0 {
+				terminal.SetSize(win.Width, win.Height)
+			}
 		}
 	}()
 
+	auditSSHSession(sess, "session_start", "", "")
+	defer auditSSHSession(sess, "session_end", "", "")
+
+	var idleC <-chan time.Time
+	var idleTimer *time.Timer
+	if sshIdleTimeout > 0 {
+		idleTimer = time.NewTimer(sshIdleTimeout)
+		defer idleTimer.Stop()
+		idleC = idleTimer.C
+	}
+
+	var deadlineC <-chan time.Time
+	if sshMaxSession > 0 {
+		deadline := time.NewTimer(sshMaxSession)
+		defer deadline.Stop()
+		deadlineC = deadline.C
+	}
+
 	// Command processing loop
 	for {
-		fmt.Fprint(sess, headerStyle.Render("devgen> "))
+		// Read the next line in the background so the loop can also watch the
+		// timers. Only one read is ever pending, and it starts after the previous
+		// command's output so the prompt follows it.
+		lines := make(chan string, 1)
+		go func() {
+			defer close(lines)
+			if l, err := terminal.ReadLine(); err == nil {
+				lines <- l
+			}
+		}()
 
 		// Read command
-		var cmd string
-		fmt.Fscanf(sess, "%s", &cmd)
-
-		switch cmd {
-		case "list":
-			handleSSHListCommand(sess, registry, renderer)
-		case "status":
-			var serverName string
-			fmt.Fscanf(sess, "%s", &serverName)
-			handleSSHStatusCommand(sess, registry, serverName, renderer)
-		case "health":
-			handleSSHHealthCommand(sess, registry, renderer)
-		case "help":
-			fmt.Fprint(sess, welcome)
-		case "exit", "quit":
-			fmt.Fprint(sess, "Goodbye! 👋\n")
+		var line string
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				// Client disconnected or pressed Ctrl+C/Ctrl+D
+				return
+			}
+			line = l
+		case <-idleC:
+			fmt.Fprintf(sess, "\nNo command received for %s, closing idle session.\n", sshIdleTimeout)
+			auditSSHSession(sess, "idle_timeout", "", "")
 			sess.Exit(0)
 			return
-		case "":
-			// Empty command, just continue
-		default:
-			fmt.Fprintf(sess, "Unknown command: %s\n", cmd)
-			fmt.Fprint(sess, "Type 'help' for available commands\n")
+		case <-deadlineC:
+			fmt.Fprintf(sess, "\nMaximum session duration of %s reached, closing session.\n", sshMaxSession)
+			auditSSHSession(sess, "session_timeout", "", "")
+			sess.Exit(0)
+			return
+		}
+
+		if idleTimer != nil {
+			if !idleTimer.Stop() {
+				select {
+				case <-idleTimer.C:
+				default:
+				}
+			}
+			idleTimer.Reset(sshIdleTimeout)
+		}
+
+		updated, quit, _ := runSSHCommand(sess, registry, strings.Fields(line), renderer)
+		registry = updated
+		if quit {
+			sess.Exit(0)
+			return
+		}
+	}
+}
+
+// sshWelcome renders the banner and command list shown in interactive sessions
+func sshWelcome(renderer *lipgloss.Renderer) string {
+	titleStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#FF10F0")).
+		Bold(true).
+		Padding(1, 2)
+
+	headerStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#00FFFF")).
+		Bold(true)
+
+	welcome := titleStyle.Render("🚀 DevGen SSH Terminal") + "\n\n" +
+		headerStyle.Render("Available Commands:") + "\n" +
+		"• list        - List all MCP servers\n" +
+		"• status <name> - Show server status\n" +
+		"• health [--json] - Check health of all servers\n"
+	if sshReadOnlyMode() {
+		welcome += "• (read-only mode: toggle is disabled)\n"
+	} else {
+		welcome += "• toggle <name> - Toggle a server on/off\n"
+	}
+	welcome += "• help        - Show this help\n" +
+		"• exit        - Close connection\n\n" +
+		"Press Tab to complete commands and server names.\n\n"
+
+	return welcome
+}
+
+// runSSHCommand executes one shell command. It returns the (possibly reloaded) registry,
+// whether the session should end, and an error when the command was rejected or unknown.
+func runSSHCommand(sess ssh.Session, registry *MCPRegistry, fields []string, renderer *lipgloss.Renderer) (*MCPRegistry, bool, error) {
+	if len(fields) == 0 {
+		// Empty command, just continue
+		return registry, false, nil
+	}
+
+	cmd := fields[0]
+	var serverName string
+	if len(fields) > 1 {
+		serverName = fields[1]
+	}
+
+	auditSSHSession(sess, "command", strings.Join(fields, " "), serverName)
+
+	if sshReadOnlyMode() && sshMutatingCommands[cmd] {
+		fmt.Fprint(sess, "command disabled in read-only mode\n")
+		return registry, false, fmt.Errorf("command disabled in read-only mode: %s", cmd)
+	}
+
+	// Read commands see edits made outside this session when refreshing is enabled
+	if sshRefreshRegistry && sshReadCommands[cmd] {
+		if current, err := reloadRegistry(); err == nil {
+			registry = current
+		} else {
+			fmt.Fprintf(sess, "Could not reload registry, showing cached data: %v\n", err)
 		}
 	}
+
+	switch cmd {
+	case "list":
+		handleSSHListCommand(sess, registry, renderer)
+	case "status":
+		if err := handleSSHStatusCommand(sess, registry, serverName, renderer); err != nil {
+			return registry, false, err
+		}
+	case "toggle":
+		updated, err := handleSSHToggleCommand(sess, serverName, renderer)
+		if err != nil {
+			return registry, false, err
+		}
+		if updated != nil {
+			return updated, false, nil
+		}
+	case "health":
+		asJSON, err := parseSSHOutputFormat(fields[1:])
+		if err != nil {
+			fmt.Fprintf(sess, "%v\n", err)
+			return registry, false, err
+		}
+		handleSSHHealthCommand(sess, registry, asJSON, renderer)
+	case "help":
+		fmt.Fprint(sess, sshWelcome(renderer))
+	case "exit", "quit":
+		fmt.Fprint(sess, "Goodbye! 👋\n")
+		return registry, true, nil
+	default:
+		fmt.Fprintf(sess, "Unknown command: %s\n", cmd)
+		fmt.Fprint(sess, "Type 'help' for available commands\n")
+		return registry, false, fmt.Errorf("unknown command: %s", cmd)
+	}
+
+	return registry, false, nil
 }
 
 func handleSSHListCommand(sess ssh.Session, registry *MCPRegistry, renderer *lipgloss.Renderer) {
@@ -916,6 +2237,11 @@ func handleSSHListCommand(sess ssh.Session, registry *MCPRegistry, renderer *lip
 
 	fmt.Fprint(sess, titleStyle.Render("🔌 MCP Server Registry")+"\n\n")
 
+	if len(registry.Servers) == 0 {
+		fmt.Fprintf(sess, "%s (%s)\n", emptyRegistryMessage, configFile)
+		return
+	}
+
 	for _, server := range registry.Servers {
 		statusText := "inactive"
 		statusStyle := statusStopped
@@ -930,7 +2256,7 @@ func handleSSHListCommand(sess ssh.Session, registry *MCPRegistry, renderer *lip
 	}
 }
 
-func handleSSHStatusCommand(sess ssh.Session, registry *MCPRegistry, serverName string, renderer *lipgloss.Renderer) {
+func handleSSHStatusCommand(sess ssh.Session, registry *MCPRegistry, serverName string, renderer *lipgloss.Renderer) error {
 	titleStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("#FF10F0")).
 		Bold(true)
@@ -941,7 +2267,7 @@ func handleSSHStatusCommand(sess ssh.Session, registry *MCPRegistry, serverName
 
 	if serverName == "" {
 		fmt.Fprint(sess, "Usage: status <server-name>\n")
-		return
+		return usageError("status needs a server name")
 	}
 
 	// Find server
@@ -955,7 +2281,7 @@ func handleSSHStatusCommand(sess ssh.Session, registry *MCPRegistry, serverName
 
 	if server == nil {
 		fmt.Fprintf(sess, "Server not found: %s\n", serverName)
-		return
+		return notFoundError("server not found: %s", serverName)
 	}
 
 	fmt.Fprint(sess, titleStyle.Render("📊 Server Status: "+server.Name)+"\n\n")
@@ -964,9 +2290,104 @@ func handleSSHStatusCommand(sess ssh.Session, registry *MCPRegistry, serverName
 	fmt.Fprintf(sess, "%s: %s\n", headerStyle.Render("Category"), server.Metadata.Category)
 	fmt.Fprintf(sess, "%s: %d\n", headerStyle.Render("Tools"), len(server.Tools))
 	fmt.Fprint(sess, "\n")
+	return nil
 }
 
-func handleSSHHealthCommand(sess ssh.Session, registry *MCPRegistry, renderer *lipgloss.Renderer) {
+// handleSSHToggleCommand toggles a server and returns the reloaded registry.
+// The registry is nil when the toggle succeeded but could not be re-read.
+func handleSSHToggleCommand(sess ssh.Session, serverName string, renderer *lipgloss.Renderer) (*MCPRegistry, error) {
+	successStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#39FF14")).
+		Bold(true)
+
+	if serverName == "" {
+		fmt.Fprint(sess, "Usage: toggle <server-name>\n")
+		return nil, usageError("toggle needs a server name")
+	}
+
+	if err := toggleServer(serverName); err != nil {
+		fmt.Fprintf(sess, "Failed to toggle %s: %v\n", serverName, err)
+		return nil, err
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		fmt.Fprintf(sess, "Toggled %s but failed to reload registry: %v\n", serverName, err)
+		return nil, nil
+	}
+
+	for _, server := range registry.Servers {
+		if server.Name == serverName {
+			fmt.Fprintf(sess, "%s %s is now %s\n", successStyle.Render("✓"), server.Name, server.Status)
+			break
+		}
+	}
+	return registry, nil
+}
+
+// sshHealthServer is one server in `health --json` output over SSH
+type sshHealthServer struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Healthy bool   `json:"healthy"`
+}
+
+// parseSSHOutputFormat reads the output format arguments of an SSH command:
+// --json, or --output-format json|text. It reports whether JSON was requested.
+func parseSSHOutputFormat(args []string) (bool, error) {
+	asJSON := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		format, hasValue := strings.CutPrefix(arg, "--output-format=")
+		switch {
+		case arg == "--json":
+			asJSON = true
+			continue
+		case arg == "--output-format":
+			if i+1 >= len(args) {
+				return false, fmt.Errorf("--output-format needs a value (json or text)")
+			}
+			i++
+			format = args[i]
+		case !hasValue:
+			return false, fmt.Errorf("unknown argument: %s", arg)
+		}
+		switch format {
+		case "json":
+			asJSON = true
+		case "text":
+			asJSON = false
+		default:
+			return false, fmt.Errorf("invalid --output-format value %q (expected json or text)", format)
+		}
+	}
+	return asJSON, nil
+}
+
+func handleSSHHealthCommand(sess ssh.Session, registry *MCPRegistry, asJSON bool, renderer *lipgloss.Renderer) {
+	if asJSON {
+		servers := make([]sshHealthServer, 0, len(registry.Servers))
+		healthy := 0
+		for _, server := range registry.Servers {
+			ok := server.Status == "active" || server.Status == "production-ready"
+			if ok {
+				healthy++
+			}
+			servers = append(servers, sshHealthServer{Name: server.Name, Status: server.Status, Healthy: ok})
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"healthy": healthy,
+			"total":   len(servers),
+			"servers": servers,
+		})
+		if err != nil {
+			fmt.Fprintf(sess, "failed to encode health: %v\n", err)
+			return
+		}
+		fmt.Fprintf(sess, "%s\n", data)
+		return
+	}
+
 	titleStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("#FF10F0")).
 		Bold(true)
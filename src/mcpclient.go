@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// mcpProtocolVersion is the MCP revision announced during initialize
+const mcpProtocolVersion = "2025-03-26"
+
+// jsonRPCRequest is an MCP request or, without an ID, a notification
+type jsonRPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int        `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *jsonRPCError   `json:"error"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpToolResult is the result of a tools/call request
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError"`
+}
+
+type mcpContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// mcpTransport sends one JSON-RPC message and, for requests, returns the matching response
+type mcpTransport interface {
+	send(ctx context.Context, req jsonRPCRequest) (*jsonRPCResponse, error)
+	close() error
+}
+
+// callMCPTool runs the initialize handshake against a server and invokes one tool
+func callMCPTool(ctx context.Context, server *MCPServer, toolName string, args map[string]interface{}) (json.RawMessage, error) {
+	defer startTiming("tool call " + toolName)()
+
+	transport, err := newMCPTransport(ctx, server.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer transport.close()
+
+	id := 1
+	_, err = mcpRequest(ctx, transport, &id, "initialize", map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "devgen", "version": "1.0.0"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initialize failed: %v", err)
+	}
+
+	if _, err := transport.send(ctx, jsonRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		return nil, fmt.Errorf("initialize failed: %v", err)
+	}
+
+	id++
+	return mcpRequest(ctx, transport, &id, "tools/call", map[string]interface{}{
+		"name":      toolName,
+		"arguments": args,
+	})
+}
+
+func mcpRequest(ctx context.Context, transport mcpTransport, id *int, method string, params interface{}) (json.RawMessage, error) {
+	resp, err := transport.send(ctx, jsonRPCRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s (code %d)", resp.Error.Message, resp.Error.Code)
+	}
+	return resp.Result, nil
+}
+
+// newMCPTransport picks stdio or HTTP from the endpoint scheme
+func newMCPTransport(ctx context.Context, endpoint string) (mcpTransport, error) {
+	switch {
+	case strings.HasPrefix(endpoint, "stdio://"):
+		return newStdioTransport(ctx, strings.TrimPrefix(endpoint, "stdio://"))
+	case strings.HasPrefix(endpoint, "http://"), strings.HasPrefix(endpoint, "https://"):
+		return &httpTransport{endpoint: endpoint, client: &http.Client{}}, nil
+	case endpoint == "":
+		return nil, fmt.Errorf("server has no endpoint")
+	default:
+		return nil, fmt.Errorf("unsupported endpoint scheme: %s", endpoint)
+	}
+}
+
+// stdioTransport talks newline-delimited JSON-RPC to a child process
+type stdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+}
+
+// resolveStdioCommand turns a stdio endpoint path into a command line. Relative
+// paths are looked up from the machina root and then the home directory.
+func resolveStdioCommand(path string) []string {
+	if !filepath.IsAbs(path) {
+		var candidates []string
+		if root := findMachinaRoot(); root != "" {
+			candidates = append(candidates, filepath.Join(root, path), filepath.Join(filepath.Dir(root), path))
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			candidates = append(candidates, filepath.Join(home, path))
+		}
+		for _, candidate := range candidates {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+	}
+
+	if strings.HasSuffix(path, ".py") {
+		return []string{"python3", path}
+	}
+	return []string{path}
+}
+
+func newStdioTransport(ctx context.Context, path string) (*stdioTransport, error) {
+	argv := resolveStdioCommand(path)
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	if verbose {
+		cmd.Stderr = os.Stderr
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", strings.Join(argv, " "), err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &stdioTransport{cmd: cmd, stdin: stdin, stdout: scanner}, nil
+}
+
+func (t *stdioTransport) send(ctx context.Context, req jsonRPCRequest) (*jsonRPCResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write to server: %v", err)
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+
+	// Skip server-initiated notifications and log lines until our response arrives
+	for t.stdout.Scan() {
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(t.stdout.Bytes(), &resp); err != nil {
+			continue
+		}
+		if resp.ID != nil && *resp.ID == *req.ID {
+			return &resp, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := t.stdout.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read from server: %v", err)
+	}
+	return nil, fmt.Errorf("server exited before responding to %s", req.Method)
+}
+
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	done := make(chan error, 1)
+	go func() { done <- t.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// httpTransport posts JSON-RPC to a streamable HTTP endpoint
+type httpTransport struct {
+	endpoint  string
+	client    *http.Client
+	sessionID string
+}
+
+func (t *httpTransport) send(ctx context.Context, req jsonRPCRequest) (*jsonRPCResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	if t.sessionID != "" {
+		httpReq.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.sessionID = id
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readSSEResponse(resp.Body, *req.ID)
+	}
+
+	var rpcResp jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &rpcResp, nil
+}
+
+// readSSEResponse scans an event stream for the response carrying the given ID
+func readSSEResponse(body io.Reader, id int) (*jsonRPCResponse, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var resp jsonRPCResponse
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &resp); err != nil {
+			continue
+		}
+		if resp.ID != nil && *resp.ID == id {
+			return &resp, nil
+		}
+	}
+	return nil, fmt.Errorf("event stream ended without a response")
+}
+
+func (t *httpTransport) close() error {
+	return nil
+}
+
+// recordToolUse bumps a tool's usage counters in the registry after an invocation
+func recordToolUse(serverName, toolName string, callErr error) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return err
+	}
+
+	for i := range registry.Tools {
+		tool := &registry.Tools[i]
+		if tool.Name != toolName || tool.ServerName != serverName {
+			continue
+		}
+		tool.UseCount++
+		if callErr != nil {
+			tool.ErrorCount++
+		}
+		tool.LastUsed = time.Now().Format(time.RFC3339)
+		return saveMCPRegistry(registry)
+	}
+
+	// Tools missing from the registry's tool index are not tracked
+	return nil
+}
+
+// invokeTool looks up a server in the registry, calls one of its tools and prints the result
+func invokeTool(serverName, toolName, rawArgs string, timeout time.Duration, asJSON bool) error {
+	args := map[string]interface{}{}
+	if rawArgs != "" {
+		if err := json.Unmarshal([]byte(rawArgs), &args); err != nil {
+			return usageError("invalid --args JSON: %v", err)
+		}
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	var server *MCPServer
+	for i := range registry.Servers {
+		if registry.Servers[i].Name == serverName {
+			server = &registry.Servers[i]
+			break
+		}
+	}
+	if server == nil {
+		return notFoundError("server not found: %s", serverName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	raw, callErr := callMCPTool(ctx, server, toolName, args)
+	duration := time.Since(start)
+
+	var result mcpToolResult
+	if callErr == nil {
+		if err := json.Unmarshal(raw, &result); err != nil {
+			callErr = fmt.Errorf("failed to decode tool result: %v", err)
+		} else if result.IsError {
+			callErr = fmt.Errorf("tool %s reported an error", toolName)
+		}
+	}
+
+	if err := recordToolUse(serverName, toolName, callErr); err != nil {
+		log.Warn("Failed to record tool usage", "error", err)
+	}
+	if err := recordToolCall(serverName, toolName, args, raw, duration, callErr); err != nil {
+		log.Warn("Failed to record tool history", "error", err)
+	}
+	logToLogfire("info", "Tool invoked", map[string]interface{}{
+		"event":       "tool_call",
+		"server_name": serverName,
+		"tool":        toolName,
+		"success":     callErr == nil,
+	})
+
+	if asJSON && raw != nil {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, raw, "", "  "); err == nil {
+			printJSON(pretty.Bytes())
+		} else {
+			printJSON(raw)
+		}
+	} else {
+		for _, content := range result.Content {
+			if content.Type == "text" {
+				printResult("%s\n", content.Text)
+			} else {
+				printResult("[%s content: %s]\n", content.Type, content.MimeType)
+			}
+		}
+	}
+
+	if callErr != nil {
+		return fmt.Errorf("%s/%s: %v", serverName, toolName, callErr)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// currentRegistryVersion is the schema version written by this build of devgen
+const currentRegistryVersion = "1.0.0"
+
+// legacyRegistryVersion is assumed for files written before the version field existed
+const legacyRegistryVersion = "0.9.0"
+
+// registryMigration upgrades a raw registry document from one schema version to the next
+type registryMigration struct {
+	from  string
+	to    string
+	apply func(doc map[string]interface{}) error
+}
+
+// Ordered list of schema migrations. Each entry must start where the previous one ended.
+var registryMigrations = []registryMigration{
+	{from: "0.9.0", to: "1.0.0", apply: migrateRegistryV090ToV100},
+}
+
+// migrateRegistryData applies any pending schema migrations to raw registry JSON.
+// It returns the upgraded JSON, the version the file started at, and whether anything changed.
+func migrateRegistryData(data []byte) ([]byte, string, bool, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, "", false, fmt.Errorf("failed to parse registry JSON: %v", err)
+	}
+	if doc == nil {
+		return data, "", false, nil
+	}
+
+	version, _ := doc["version"].(string)
+	if version == "" {
+		version = legacyRegistryVersion
+	}
+	startVersion := version
+
+	for version != currentRegistryVersion {
+		migration := findRegistryMigration(version)
+		if migration == nil {
+			// Unknown or newer schema: leave the document untouched
+			return data, startVersion, false, nil
+		}
+		if err := migration.apply(doc); err != nil {
+			return nil, startVersion, false, fmt.Errorf("failed to migrate registry from %s to %s: %v", migration.from, migration.to, err)
+		}
+		version = migration.to
+		doc["version"] = version
+	}
+
+	if version == startVersion {
+		return data, startVersion, false, nil
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, startVersion, false, fmt.Errorf("failed to marshal migrated registry: %v", err)
+	}
+	return migrated, startVersion, true, nil
+}
+
+func findRegistryMigration(version string) *registryMigration {
+	for i := range registryMigrations {
+		if registryMigrations[i].from == version {
+			return &registryMigrations[i]
+		}
+	}
+	return nil
+}
+
+// pendingMigration is the original contents of a registry file that was
+// migrated on load but not yet written back
+type pendingMigration struct {
+	data    []byte
+	version string
+}
+
+// Registry files migrated on load, by path, until the upgraded file is written
+var (
+	pendingMigrationsMu sync.Mutex
+	pendingMigrations   = map[string]pendingMigration{}
+)
+
+// rememberMigration records that path was loaded from an older schema, so the
+// first write backs up the original before replacing it
+func rememberMigration(path string, data []byte, version string) {
+	pendingMigrationsMu.Lock()
+	defer pendingMigrationsMu.Unlock()
+	if _, ok := pendingMigrations[path]; !ok {
+		pendingMigrations[path] = pendingMigration{data: data, version: version}
+	}
+}
+
+// backupMigratedFile writes the pre-migration backup of path if it was
+// migrated on load and has not been written since
+func backupMigratedFile(path string) error {
+	pendingMigrationsMu.Lock()
+	defer pendingMigrationsMu.Unlock()
+
+	pending, ok := pendingMigrations[path]
+	if !ok {
+		return nil
+	}
+	backupPath, err := backupRegistryFile(path, pending.data, pending.version)
+	if err != nil {
+		return err
+	}
+	delete(pendingMigrations, path)
+	log.Info("Migrated registry schema", "file", path, "from", pending.version, "to", currentRegistryVersion, "backup", backupPath)
+	return nil
+}
+
+// backupRegistryFile copies the pre-migration registry next to the original
+func backupRegistryFile(path string, data []byte, version string) (string, error) {
+	backupPath := fmt.Sprintf("%s.v%s.bak", path, version)
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write registry backup: %v", err)
+	}
+	return backupPath, nil
+}
+
+// 0.9.0 -> 1.0.0: environment variables moved from "env_vars" to "environment_vars"
+// and health tracking fields were introduced on every server.
+func migrateRegistryV090ToV100(doc map[string]interface{}) error {
+	servers, _ := doc["servers"].([]interface{})
+	for _, entry := range servers {
+		server, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if metadata, ok := server["metadata"].(map[string]interface{}); ok {
+			if envVars, ok := metadata["env_vars"]; ok {
+				if _, exists := metadata["environment_vars"]; !exists {
+					metadata["environment_vars"] = envVars
+				}
+				delete(metadata, "env_vars")
+			}
+		}
+
+		if _, ok := server["health_check_failures"]; !ok {
+			server["health_check_failures"] = 0
+		}
+		if _, ok := server["last_seen"]; !ok {
+			server["last_seen"] = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const v090Fixture = `{
+  "timestamp": "2024-01-01T00:00:00",
+  "servers": [
+    {
+      "name": "legacy-mcp",
+      "endpoint": "http://127.0.0.1:9000",
+      "tools": ["search"],
+      "status": "active",
+      "metadata": {"category": "web", "env_vars": ["LEGACY_TOKEN"]}
+    }
+  ]
+}`
+
+// useTestRegistry points configFile at a fresh registry file in a temporary
+// directory, which is also the working directory and the config directory so
+// debug logs and backups stay there
+func useTestRegistry(t *testing.T, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mcp_status.json")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	oldConfig, oldConfigDir := configFile, configDir
+	configFile, configDir = path, dir
+	invalidateRegistryCache()
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		configFile, configDir = oldConfig, oldConfigDir
+		invalidateRegistryCache()
+	})
+	return path
+}
+
+func TestLoadMigratesV090Registry(t *testing.T) {
+	path := useTestRegistry(t, v090Fixture)
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		t.Fatalf("loadMCPRegistry: %v", err)
+	}
+	if registry.Version != currentRegistryVersion {
+		t.Errorf("Version = %q, want %q", registry.Version, currentRegistryVersion)
+	}
+	if len(registry.Servers) != 1 {
+		t.Fatalf("got %d servers, want 1", len(registry.Servers))
+	}
+	server := registry.Servers[0]
+	if got := server.Metadata.EnvironmentVars; len(got) != 1 || got[0] != "LEGACY_TOKEN" {
+		t.Errorf("EnvironmentVars = %v, want [LEGACY_TOKEN]", got)
+	}
+	if server.HealthCheckFails != 0 || server.LastSeen != nil {
+		t.Errorf("health fields = %d, %v, want 0, nil", server.HealthCheckFails, server.LastSeen)
+	}
+
+	// Loading writes the backup and the upgraded schema
+	backup, err := os.ReadFile(path + ".v0.9.0.bak")
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != v090Fixture {
+		t.Error("backup does not hold the original file")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct {
+		Version string `json:"version"`
+		Servers []struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		} `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("saved registry is not valid JSON: %v", err)
+	}
+	if saved.Version != currentRegistryVersion {
+		t.Errorf("saved version = %q, want %q", saved.Version, currentRegistryVersion)
+	}
+	if _, ok := saved.Servers[0].Metadata["env_vars"]; ok {
+		t.Error("saved registry still has env_vars")
+	}
+	if _, ok := saved.Servers[0].Metadata["environment_vars"]; !ok {
+		t.Error("saved registry is missing environment_vars")
+	}
+}
+
+func TestLoadReadOnlyV090Registry(t *testing.T) {
+	path := useTestRegistry(t, v090Fixture)
+	dir := filepath.Dir(path)
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+	if isWritableDir(dir) {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		t.Fatalf("loading an old registry in a read-only directory failed: %v", err)
+	}
+	if registry.Version != currentRegistryVersion {
+		t.Errorf("Version = %q, want %q", registry.Version, currentRegistryVersion)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != v090Fixture {
+		t.Error("registry file in a read-only directory was modified")
+	}
+}
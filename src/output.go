@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// isTerminal reports whether f is attached to an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// renderTable renders rows as a bordered lipgloss table. statusCol, if >= 0,
+// is colorized green/red according to isActiveStatus.
+func renderTable(headers []string, rows [][]string, statusCol int) string {
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#00FFFF"))).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			cell := lipgloss.NewStyle().Padding(0, 1)
+			if row == table.HeaderRow {
+				return headerStyle.Padding(0, 1)
+			}
+			if col == statusCol && row < len(rows) {
+				if isActiveStatus(rows[row][col]) {
+					return statusRunning.Padding(0, 1)
+				}
+				if rows[row][col] != "unknown" {
+					return statusStopped.Padding(0, 1)
+				}
+			}
+			return cell
+		})
+	return t.Render()
+}
+
+// renderTSV renders rows as tab-separated values for piping into other tools
+func renderTSV(headers []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(headers, "\t"))
+	b.WriteString("\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+		}
+		b.WriteString(strings.Join(cells, "\t"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseItemTemplate parses a --template value so syntax errors are reported
+// before any work is done. Unknown fields only show up when the template runs,
+// and renderItemTemplate reports them against the item that failed.
+func parseItemTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, usageError("invalid --template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderItemTemplate applies tmpl to each item, one item per line
+func renderItemTemplate[T any](tmpl *template.Template, items []T) (string, error) {
+	var b strings.Builder
+	for i, item := range items {
+		start := b.Len()
+		if err := tmpl.Execute(&b, item); err != nil {
+			return "", usageError("--template failed on item %d: %v", i+1, err)
+		}
+		if b.Len() == start || !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// printProgress prints decorative progress and heading lines, which --quiet suppresses
+func printProgress(format string, args ...interface{}) {
+	if quiet {
+		return
+	}
+	fmt.Print(stripEmojiIfDisabled(fmt.Sprintf(format, args...)))
+}
+
+// printResult prints results and errors, which are always shown
+func printResult(format string, args ...interface{}) {
+	fmt.Print(stripEmojiIfDisabled(fmt.Sprintf(format, args...)))
+}
+
+// printJSON prints a machine-readable payload unchanged; --no-emoji only
+// applies to plain-text output
+func printJSON(data []byte) {
+	fmt.Printf("%s\n", data)
+}
+
+func stripEmojiIfDisabled(s string) string {
+	if !noEmoji {
+		return s
+	}
+	return stripEmoji(s)
+}
+
+// isEmoji reports whether r is a pictographic rune or an emoji joiner/variation selector
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, transport, symbols
+		return true
+	case r >= 0x2600 && r <= 0x26FF: // miscellaneous symbols (⚡, ⚠)
+		return true
+	case r >= 0x23E9 && r <= 0x23FA: // media and clock symbols (⏳)
+		return true
+	case r == 0x2705, r == 0x274C, r == 0x274E, r == 0x2728, r == 0x2B50, r == 0x2B55:
+		return true
+	case r >= 0x2753 && r <= 0x2757:
+		return true
+	case r == 0xFE0F, r == 0x200D: // variation selector and zero width joiner
+		return true
+	}
+	return false
+}
+
+// stripEmoji removes emoji along with the space that followed them at the start of a
+// word, so "✅ Done" becomes "Done" rather than " Done"
+func stripEmoji(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if !isEmoji(r) {
+			b.WriteRune(r)
+			continue
+		}
+		// Skip the rest of an emoji sequence, then one separating space
+		for i+1 < len(runes) && isEmoji(runes[i+1]) {
+			i++
+		}
+		out := b.String()
+		atWordStart := len(out) == 0 || strings.HasSuffix(out, " ") || strings.HasSuffix(out, "\n")
+		for atWordStart && i+1 < len(runes) && runes[i+1] == ' ' {
+			i++
+		}
+	}
+	return b.String()
+}
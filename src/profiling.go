@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Developer profiling flags; both are hidden from help
+var (
+	pprofCPUFile string
+	pprofMemFile string
+
+	cpuProfile *os.File
+)
+
+// startProfiling begins CPU profiling when --pprof-cpu is set
+func startProfiling() error {
+	if pprofCPUFile == "" {
+		return nil
+	}
+	file, err := os.Create(pprofCPUFile)
+	if err != nil {
+		return fmt.Errorf("failed to create CPU profile: %v", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to start CPU profile: %v", err)
+	}
+	cpuProfile = file
+	return nil
+}
+
+// stopProfiling finishes the CPU profile and writes a heap profile when
+// requested. Failures are reported on stderr so they never mask the command's
+// own result.
+func stopProfiling() {
+	if cpuProfile != nil {
+		pprof.StopCPUProfile()
+		cpuProfile.Close()
+		cpuProfile = nil
+	}
+
+	if pprofMemFile == "" {
+		return
+	}
+	file, err := os.Create(pprofMemFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create heap profile: %v\n", err)
+		return
+	}
+	defer file.Close()
+	runtime.GC() // report up-to-date allocation statistics
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write heap profile: %v\n", err)
+	}
+}
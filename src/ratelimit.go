@@ -0,0 +1,143 @@
+package main
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// authLimiter tracks failed SSH authentication attempts per remote IP and
+// blocks an IP for a cooldown period once it exceeds the allowed failures.
+type authLimiter struct {
+	mu          sync.Mutex
+	maxFailures int
+	window      time.Duration
+	cooldown    time.Duration
+	clients     map[string]*authAttempts
+}
+
+type authAttempts struct {
+	failures     []time.Time
+	blockedUntil time.Time
+}
+
+// maxTrackedClients caps how many IPs the limiter remembers, so a flood of
+// failures from many addresses cannot grow memory without bound
+const maxTrackedClients = 10000
+
+// expiresAt is when the entry stops mattering: its last failure has left the
+// window and any block has ended
+func (a *authAttempts) expiresAt(window time.Duration) time.Time {
+	expires := a.blockedUntil
+	if n := len(a.failures); n > 0 && a.failures[n-1].Add(window).After(expires) {
+		expires = a.failures[n-1].Add(window)
+	}
+	return expires
+}
+
+func newAuthLimiter(maxFailures int, window, cooldown time.Duration) *authLimiter {
+	return &authLimiter{
+		maxFailures: maxFailures,
+		window:      window,
+		cooldown:    cooldown,
+		clients:     make(map[string]*authAttempts),
+	}
+}
+
+// remoteIP strips the port from a remote address so all connections from a host share a bucket
+func remoteIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// allowed reports whether the IP may attempt authentication right now
+func (l *authLimiter) allowed(ip string) bool {
+	if l == nil || l.maxFailures <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	client, ok := l.clients[ip]
+	if !ok {
+		return true
+	}
+	now := time.Now()
+	if now.After(client.expiresAt(l.window)) {
+		delete(l.clients, ip)
+		return true
+	}
+	return now.After(client.blockedUntil)
+}
+
+// prune drops expired entries and, if the limiter is still full, the entries
+// closest to expiring until there is room for one more. Callers hold l.mu.
+func (l *authLimiter) prune(now time.Time) {
+	for ip, client := range l.clients {
+		if now.After(client.expiresAt(l.window)) {
+			delete(l.clients, ip)
+		}
+	}
+	for len(l.clients) >= maxTrackedClients {
+		var oldestIP string
+		var oldest time.Time
+		for ip, client := range l.clients {
+			if expires := client.expiresAt(l.window); oldestIP == "" || expires.Before(oldest) {
+				oldestIP, oldest = ip, expires
+			}
+		}
+		delete(l.clients, oldestIP)
+	}
+}
+
+// recordFailure counts a failed attempt and reports whether the IP is now blocked
+func (l *authLimiter) recordFailure(ip string) bool {
+	if l == nil || l.maxFailures <= 0 {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	client, ok := l.clients[ip]
+	if !ok {
+		l.prune(now)
+		client = &authAttempts{}
+		l.clients[ip] = client
+	}
+
+	// Only failures inside the window count towards the limit
+	recent := client.failures[:0]
+	for _, t := range client.failures {
+		if now.Sub(t) <= l.window {
+			recent = append(recent, t)
+		}
+	}
+	client.failures = append(recent, now)
+
+	if len(client.failures) >= l.maxFailures {
+		client.blockedUntil = now.Add(l.cooldown)
+		client.failures = nil
+		return true
+	}
+	return false
+}
+
+// recordSuccess clears the failure history for an IP
+func (l *authLimiter) recordSuccess(ip string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, ip)
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"text/template"
 	"time"
+
+	"github.com/charmbracelet/log"
 )
 
 // HTTP Registry Types
@@ -24,68 +34,415 @@ type HTTPRegistryTool struct {
 	Description string `json:"description"`
 }
 
+// registryStatusReport is the result of probing the HTTP registry
+type registryStatusReport struct {
+	URL       string `json:"url"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+	Cause     string `json:"cause,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Version   string `json:"version,omitempty"`
+	Uptime    string `json:"uptime,omitempty"`
+	Servers   int    `json:"servers"`
+	Tools     int    `json:"tools"`
+}
+
+// registryInfo is the body returned by the registry's /health or /info endpoint.
+// Field names vary between registry builds, so both spellings are accepted.
+type registryInfo struct {
+	Version     string      `json:"version"`
+	Uptime      interface{} `json:"uptime"`
+	UptimeSecs  float64     `json:"uptime_seconds"`
+	Servers     *int        `json:"servers"`
+	ServerCount *int        `json:"server_count"`
+	Tools       *int        `json:"tools"`
+	ToolCount   *int        `json:"tool_count"`
+}
+
 // Registry management functions
-func checkRegistryStatus() error {
-	client := &http.Client{Timeout: 5 * time.Second}
-	
-	fmt.Printf("🔍 Checking MCP Registry Status\n")
-	fmt.Printf("Registry URL: %s\n", registryURL)
-	
-	// Check servers endpoint
+func checkRegistryStatus(asJSON bool) error {
+	report := probeRegistry(registryClient)
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		if !report.Reachable {
+			return fmt.Errorf("registry not accessible: %s", report.Error)
+		}
+		return nil
+	}
+
+	printProgress("🔍 Checking MCP Registry Status\n")
+	printProgress("Registry URL: %s\n", registryURL)
+
+	if !report.Reachable {
+		printResult("❌ Registry not accessible (%s): %s\n", report.Cause, report.Error)
+		return fmt.Errorf("registry not accessible: %s", report.Error)
+	}
+
+	printResult("✅ Registry is active (%dms)\n", report.LatencyMs)
+	if report.Version != "" {
+		printResult("🏷️  Version: %s\n", report.Version)
+	}
+	if report.Uptime != "" {
+		printResult("⏱️  Uptime: %s\n", report.Uptime)
+	}
+	printResult("📊 Registered servers: %d\n", report.Servers)
+	printResult("🛠️  Registered tools: %d\n", report.Tools)
+
+	return nil
+}
+
+// probeRegistry asks the registry for its /health (or /info) document and falls back
+// to counting /servers and /tools for registries that expose neither
+func probeRegistry(client *http.Client) registryStatusReport {
+	report := registryStatusReport{URL: registryURL}
+
+	defer startTiming("registry request")()
+
+	start := time.Now()
+	info, err := fetchRegistryInfo(client)
+	report.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		report.Error = err.Error()
+		report.Cause = classifyRegistryError(err)
+		return report
+	}
+	report.Reachable = true
+
+	if info != nil {
+		report.Version = info.Version
+		report.Uptime = formatRegistryUptime(info)
+		if count := firstCount(info.Servers, info.ServerCount); count != nil {
+			report.Servers = *count
+		} else {
+			report.Servers = countRegistryItems(client, "/servers")
+		}
+		if count := firstCount(info.Tools, info.ToolCount); count != nil {
+			report.Tools = *count
+		} else {
+			report.Tools = countRegistryItems(client, "/tools")
+		}
+		return report
+	}
+
+	report.Servers = countRegistryItems(client, "/servers")
+	report.Tools = countRegistryItems(client, "/tools")
+	return report
+}
+
+// fetchRegistryInfo returns nil info (and no error) when the registry is up but has
+// no health endpoint; an error means the registry could not be reached at all
+func fetchRegistryInfo(client *http.Client) (*registryInfo, error) {
+	for _, endpoint := range []string{"/health", "/info"} {
+		resp, err := client.Get(registryURL + endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			continue
+		}
+
+		var info registryInfo
+		err = json.NewDecoder(resp.Body).Decode(&info)
+		resp.Body.Close()
+		if err == nil {
+			return &info, nil
+		}
+	}
+
+	// Neither endpoint exists; make sure the registry itself is serving
 	resp, err := client.Get(registryURL + "/servers")
 	if err != nil {
-		fmt.Printf("❌ Registry not accessible: %v\n", err)
-		return err
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned status %d", resp.StatusCode)
+	}
+	return nil, nil
+}
+
+// countRegistryItems returns the length of a JSON array endpoint, or 0 if it can't be read
+func countRegistryItems(client *http.Client, endpoint string) int {
+	resp, err := client.Get(registryURL + endpoint)
+	if err != nil {
+		return 0
 	}
 	defer resp.Body.Close()
-	
-	if resp.StatusCode != 200 {
-		fmt.Printf("❌ Registry returned status %d\n", resp.StatusCode)
-		return fmt.Errorf("registry returned status %d", resp.StatusCode)
+
+	var items []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return 0
 	}
-	
-	var servers []HTTPRegistryServer
-	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
-		fmt.Printf("❌ Failed to decode response: %v\n", err)
-		return err
+	return len(items)
+}
+
+func firstCount(counts ...*int) *int {
+	for _, count := range counts {
+		if count != nil {
+			return count
+		}
 	}
-	
-	fmt.Printf("✅ Registry is active\n")
-	fmt.Printf("📊 Registered servers: %d\n", len(servers))
-	
 	return nil
 }
 
-func listRegistryServers() error {
-	client := &http.Client{Timeout: 5 * time.Second}
-	
-	resp, err := client.Get(registryURL + "/servers")
+// formatRegistryUptime accepts uptime as a preformatted string or as seconds
+func formatRegistryUptime(info *registryInfo) string {
+	switch uptime := info.Uptime.(type) {
+	case string:
+		return uptime
+	case float64:
+		return (time.Duration(uptime) * time.Second).String()
+	}
+	if info.UptimeSecs > 0 {
+		return (time.Duration(info.UptimeSecs) * time.Second).String()
+	}
+	return ""
+}
+
+// classifyRegistryError names the reason a registry request failed
+func classifyRegistryError(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return "dns"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return "connection refused"
+	}
+	if strings.HasPrefix(err.Error(), "registry returned status") {
+		return "http"
+	}
+	return "connection"
+}
+
+// registryServerRow is one line of `registry servers` output: the HTTP registry
+// entry enriched with the matching record from the local registry file, if any
+type registryServerRow struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	URL              string `json:"url"`
+	Port             int    `json:"port"`
+	Status           string `json:"status"`
+	Category         string `json:"category"`
+	Tools            int    `json:"tools"`
+	LastSeen         string `json:"last_seen"`
+	Endpoint         string `json:"endpoint"`
+	Version          string `json:"version"`
+	RegisteredAt     string `json:"registered_at"`
+	HealthCheckFails int    `json:"health_check_failures"`
+}
+
+func fetchRegistryServers() ([]HTTPRegistryServer, error) {
+	resp, err := registryClient.Get(registryURL + "/servers")
 	if err != nil {
-		return fmt.Errorf("failed to connect to registry: %v", err)
+		return nil, fmt.Errorf("failed to connect to registry: %v", err)
 	}
 	defer resp.Body.Close()
-	
+
 	var servers []HTTPRegistryServer
 	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
-		return fmt.Errorf("failed to decode response: %v", err)
+		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
-	
-	fmt.Printf("🔌 MCP Registry Servers (%d total)\n\n", len(servers))
-	
-	for i, server := range servers {
-		fmt.Printf("%d. %s\n", i+1, statusRunning.Render(server.Name))
-		fmt.Printf("   📝 Description: %s\n", server.Description)
-		fmt.Printf("   🌐 URL: %s:%d\n", server.URL, server.Port)
-		fmt.Printf("\n")
+	return servers, nil
+}
+
+// buildServerRows joins HTTP registry servers with the local registry by name
+func buildServerRows(servers []HTTPRegistryServer) []registryServerRow {
+	local := make(map[string]MCPServer)
+	if registry, err := loadMCPRegistry(); err == nil {
+		for _, server := range registry.Servers {
+			local[server.Name] = server
+		}
 	}
-	
+
+	rows := make([]registryServerRow, 0, len(servers))
+	for _, server := range servers {
+		row := registryServerRow{
+			Name:         server.Name,
+			Description:  server.Description,
+			URL:          server.URL,
+			Port:         server.Port,
+			Status:       "unknown",
+			Category:     "-",
+			LastSeen:     "-",
+			Endpoint:     server.URL,
+			Version:      "-",
+			RegisteredAt: "-",
+		}
+		if record, ok := local[server.Name]; ok {
+			row.Status = record.Status
+			row.Category = serverCategory(record)
+			row.Tools = len(record.Tools)
+			row.LastSeen = "never"
+			if record.LastSeen != nil && *record.LastSeen != "" {
+				row.LastSeen = *record.LastSeen
+			}
+			if record.Endpoint != "" {
+				row.Endpoint = record.Endpoint
+			}
+			if record.Version != "" {
+				row.Version = record.Version
+			}
+			if record.RegisteredAt != "" {
+				row.RegisteredAt = record.RegisteredAt
+			}
+			row.HealthCheckFails = record.HealthCheckFails
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// serverListOptions are the `registry servers` flags
+type serverListOptions struct {
+	format     string
+	statuses   []string
+	categories []string
+	asJSON     bool
+	template   string
+	sortKey    string
+	reverse    bool
+}
+
+// matchesAny reports whether value equals one of wanted (case-insensitive).
+// An empty list matches everything.
+func matchesAny(value string, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, w := range wanted {
+		if strings.EqualFold(value, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesStatus reports whether status matches any wanted status. A wanted
+// "active" also matches the other running statuses, as isActiveStatus does.
+func matchesStatus(status string, wanted []string) bool {
+	if matchesAny(status, wanted) {
+		return true
+	}
+	return matchesAny("active", wanted) && isActiveStatus(status)
+}
+
+// filterServerRows keeps rows matching any of the given statuses and any of the given categories
+func filterServerRows(rows []registryServerRow, statuses, categories []string) []registryServerRow {
+	filtered := []registryServerRow{}
+	for _, row := range rows {
+		if matchesStatus(row.Status, statuses) && matchesAny(row.Category, categories) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+func listRegistryServers(opts serverListOptions) error {
+	var tmpl *template.Template
+	if opts.template != "" {
+		var err error
+		if tmpl, err = parseItemTemplate(opts.template); err != nil {
+			return err
+		}
+	}
+
+	servers, err := fetchRegistryServers()
+	if err != nil {
+		return err
+	}
+	rows := filterServerRows(buildServerRows(servers), opts.statuses, opts.categories)
+	if opts.sortKey != "" {
+		sortServerRows(rows, opts.sortKey, opts.reverse)
+	}
+
+	if tmpl != nil {
+		out, err := renderItemTemplate(tmpl, rows)
+		if err != nil {
+			return err
+		}
+		printResult("%s", out)
+		return nil
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	headers := []string{"NAME", "STATUS", "CATEGORY", "TOOLS", "LAST SEEN"}
+	if opts.format == "wide" {
+		headers = append(headers, "ENDPOINT", "VERSION", "REGISTERED", "FAILS")
+	}
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		cell := []string{row.Name, row.Status, row.Category, strconv.Itoa(row.Tools), row.LastSeen}
+		if opts.format == "wide" {
+			cell = append(cell, row.Endpoint, row.Version, row.RegisteredAt, strconv.Itoa(row.HealthCheckFails))
+		}
+		cells = append(cells, cell)
+	}
+
+	// Plain TSV when piped so the output stays easy to parse
+	if !isTerminal(os.Stdout) {
+		printResult("%s", renderTSV(headers, cells))
+		return nil
+	}
+
+	printProgress("🔌 MCP Registry Servers (%d total)\n\n", len(rows))
+	printResult("%s\n", renderTable(headers, cells, 1))
+
 	return nil
 }
 
-func listRegistryTools() error {
-	client := &http.Client{Timeout: 5 * time.Second}
-	
-	resp, err := client.Get(registryURL + "/tools")
+// toolListOptions are the filters and paging for `registry tools`
+type toolListOptions struct {
+	template string
+	search   string
+	servers  []string
+	limit    int
+	offset   int
+	asJSON   bool
+}
+
+// toolServerName returns the server part of a registry tool name ("server.tool")
+func toolServerName(tool HTTPRegistryTool) (server, name string) {
+	if server, name, found := strings.Cut(tool.Name, "."); found {
+		return server, name
+	}
+	return "Unknown", tool.Name
+}
+
+// matchesToolSearch reports whether a tool name or description contains query (case-insensitive)
+func matchesToolSearch(tool HTTPRegistryTool, query string) bool {
+	query = strings.ToLower(query)
+	return strings.Contains(strings.ToLower(tool.Name), query) || strings.Contains(strings.ToLower(tool.Description), query)
+}
+
+func listRegistryTools(opts toolListOptions) error {
+	var tmpl *template.Template
+	if opts.template != "" {
+		var err error
+		if tmpl, err = parseItemTemplate(opts.template); err != nil {
+			return err
+		}
+	}
+
+	resp, err := registryClient.Get(registryURL + "/tools")
 	if err != nil {
 		return fmt.Errorf("failed to connect to registry: %v", err)
 	}
@@ -95,40 +452,92 @@ func listRegistryTools() error {
 	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
 		return fmt.Errorf("failed to decode response: %v", err)
 	}
+
+	matched := []HTTPRegistryTool{}
+	for _, tool := range tools {
+		server, _ := toolServerName(tool)
+		if matchesAny(server, opts.servers) && (opts.search == "" || matchesToolSearch(tool, opts.search)) {
+			matched = append(matched, tool)
+		}
+	}
+
+	// Page through the matches in registry order
+	page := matched[min(opts.offset, len(matched)):]
+	if opts.limit > 0 && len(page) > opts.limit {
+		page = page[:opts.limit]
+	}
+
+	if tmpl != nil {
+		out, err := renderItemTemplate(tmpl, page)
+		if err != nil {
+			return err
+		}
+		printResult("%s", out)
+		return nil
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"total":  len(matched),
+			"offset": opts.offset,
+			"limit":  opts.limit,
+			"count":  len(page),
+			"tools":  page,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
 	
-	fmt.Printf("🛠️  MCP Registry Tools (%d total)\n\n", len(tools))
+	printProgress("🛠️  MCP Registry Tools (%d total)\n\n", len(matched))
 	
-	// Group tools by server
+	// Group the page by server, in the order servers first appear
+	var servers []string
 	toolsByServer := make(map[string][]string)
-	for _, tool := range tools {
-		if strings.Contains(tool.Name, ".") {
-			serverName := strings.Split(tool.Name, ".")[0]
-			toolName := strings.Split(tool.Name, ".")[1]
-			toolsByServer[serverName] = append(toolsByServer[serverName], toolName)
-		} else {
-			toolsByServer["Unknown"] = append(toolsByServer["Unknown"], tool.Name)
+	for _, tool := range page {
+		server, name := toolServerName(tool)
+		if _, seen := toolsByServer[server]; !seen {
+			servers = append(servers, server)
 		}
+		toolsByServer[server] = append(toolsByServer[server], name)
 	}
 	
-	for serverName, serverTools := range toolsByServer {
-		fmt.Printf("📦 %s (%d tools):\n", headerStyle.Render(serverName), len(serverTools))
+	for _, serverName := range servers {
+		serverTools := toolsByServer[serverName]
+		printResult("📦 %s (%d tools):\n", headerStyle.Render(serverName), len(serverTools))
 		for _, tool := range serverTools {
-			fmt.Printf("   • %s\n", tool)
+			printResult("   • %s\n", tool)
+		}
+		printResult("\n")
+	}
+
+	if len(page) < len(matched) {
+		if len(page) == 0 {
+			printResult("No tools at offset %d of %d\n", opts.offset, len(matched))
+		} else {
+			printResult("Showing %d-%d of %d tools\n", opts.offset+1, opts.offset+len(page), len(matched))
+		}
+		if next := opts.offset + len(page); len(page) > 0 && next < len(matched) {
+			printProgress("Next page: --offset %d --limit %d\n", next, opts.limit)
 		}
-		fmt.Printf("\n")
 	}
 	
 	return nil
 }
 
-func startMCPRegistry() error {
-	fmt.Printf("🚀 Starting MCP Registry...\n")
+// startMCPRegistry launches the registry script unless a registry already answers.
+// With wait it polls until the registry responds or waitTimeout passes, instead
+// of checking once after a fixed delay.
+func startMCPRegistry(wait bool, waitTimeout time.Duration) error {
+	printProgress("🚀 Starting MCP Registry...\n")
 	
 	// Check if already running
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := registryClientWithTimeout(2 * time.Second)
 	if resp, err := client.Get(registryURL + "/servers"); err == nil {
 		resp.Body.Close()
-		fmt.Printf("✅ Registry already running at %s\n", registryURL)
+		printResult("✅ Registry already running at %s\n", registryURL)
 		return nil
 	}
 	
@@ -146,7 +555,7 @@ func startMCPRegistry() error {
 		}
 	}
 	
-	fmt.Printf("📂 Found registry script: %s\n", registryPath)
+	printProgress("📂 Found registry script: %s\n", registryPath)
 	
 	// Start the registry in background
 	cmd := exec.Command("python3", registryPath)
@@ -155,16 +564,1068 @@ func startMCPRegistry() error {
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start registry: %v", err)
 	}
+
+	if wait {
+		return waitForRegistry(cmd, waitTimeout)
+	}
 	
-	fmt.Printf("⏳ Waiting for registry to start...\n")
+	printProgress("⏳ Waiting for registry to start...\n")
 	time.Sleep(3 * time.Second)
 	
 	// Check if it started successfully
 	if resp, err := client.Get(registryURL + "/servers"); err == nil {
 		resp.Body.Close()
-		fmt.Printf("✅ Registry started successfully at %s\n", registryURL)
+		printResult("✅ Registry started successfully at %s\n", registryURL)
 		return nil
 	} else {
 		return fmt.Errorf("registry failed to start: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+// waitForRegistry polls the registry's health endpoint until it answers, the
+// started process fails, or timeout passes
+func waitForRegistry(cmd *exec.Cmd, timeout time.Duration) error {
+	defer startTiming("registry startup")()
+	printProgress("⏳ Waiting up to %s for registry to respond...\n", timeout)
+
+	start := time.Now()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	client := registryClientWithTimeout(2 * time.Second)
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		_, err := fetchRegistryInfo(client)
+		if err == nil {
+			printResult("✅ Registry ready at %s (started in %s)\n", registryURL, formatElapsed(time.Since(start)))
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case err := <-exited:
+			// A clean exit may mean the script handed off to a background
+			// process, so keep polling; a failure means it will never answer
+			if err != nil {
+				return fmt.Errorf("registry process exited before it was ready: %v", err)
+			}
+			exited = nil
+		case <-deadline:
+			return fmt.Errorf("registry did not respond at %s within %s: %v", registryURL, timeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseAge parses a duration that also accepts a day suffix, e.g. "7d" or "36h"
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// parseRegistryTime parses timestamps as written by the registry (with or without a zone)
+func parseRegistryTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isPrunable reports whether a server has failed enough health checks and has not been seen recently
+func isPrunable(server MCPServer, maxFails int, cutoff time.Time) bool {
+	if server.HealthCheckFails <= maxFails {
+		return false
+	}
+	if server.LastSeen == nil || *server.LastSeen == "" {
+		return true
+	}
+	lastSeen, ok := parseRegistryTime(*server.LastSeen)
+	return !ok || lastSeen.Before(cutoff)
+}
+
+func pruneRegistryServers(maxFails int, olderThan time.Duration, dryRun, yes bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	keep := []MCPServer{}
+	var pruned []MCPServer
+	for _, server := range registry.Servers {
+		if isPrunable(server, maxFails, cutoff) {
+			pruned = append(pruned, server)
+		} else {
+			keep = append(keep, server)
+		}
+	}
+
+	if len(pruned) == 0 {
+		printResult("✅ No servers to prune\n")
+		return nil
+	}
+
+	printResult("🧹 Servers to prune (%d):\n\n", len(pruned))
+	for _, server := range pruned {
+		lastSeen := "never"
+		if server.LastSeen != nil && *server.LastSeen != "" {
+			lastSeen = *server.LastSeen
+		}
+		printResult("   • %s (failures: %d, last seen: %s)\n", server.Name, server.HealthCheckFails, lastSeen)
+	}
+	printResult("\n")
+
+	if dryRun {
+		printResult("Dry run: registry not modified\n")
+		return nil
+	}
+
+	if !yes {
+		printResult("Remove these servers from %s? (y/N): ", configFile)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			printResult("Aborted\n")
+			return nil
+		}
+	}
+
+	// Drop the tools that belonged to pruned servers as well
+	prunedNames := make(map[string]bool)
+	for _, server := range pruned {
+		prunedNames[server.Name] = true
+	}
+	tools := []MCPTool{}
+	for _, tool := range registry.Tools {
+		if !prunedNames[tool.ServerName] {
+			tools = append(tools, tool)
+		}
+	}
+
+	registry.Servers = keep
+	registry.Tools = tools
+	if err := saveMCPRegistry(registry); err != nil {
+		return err
+	}
+
+	for _, server := range pruned {
+		logRegistryEvent("prune", server.Name, server.Status, "removed")
+	}
+
+	printResult("✅ Pruned %d servers\n", len(pruned))
+	return nil
+}
+
+// readRegistryFile loads a registry file other than the active one, applying schema
+// migrations in memory only
+func readRegistryFile(path string) (*MCPRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %v", err)
+	}
+
+	migrated, _, _, err := migrateRegistryData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var registry MCPRegistry
+	if err := json.Unmarshal(migrated, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse registry JSON: %v", err)
+	}
+	normalizeMCPRegistry(&registry)
+	return &registry, nil
+}
+
+// validateMCPRegistry returns a list of structural problems with a registry
+func validateMCPRegistry(registry *MCPRegistry) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for i, server := range registry.Servers {
+		if server.Name == "" {
+			problems = append(problems, fmt.Sprintf("server #%d has no name", i+1))
+			continue
+		}
+		if seen[server.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate server name %q", server.Name))
+		}
+		seen[server.Name] = true
+		if server.Endpoint == "" {
+			problems = append(problems, fmt.Sprintf("server %q has no endpoint", server.Name))
+		}
+		if argv := healthCheckCommand(server.Metadata.HealthCheck); argv != nil && len(argv) == 0 {
+			problems = append(problems, fmt.Sprintf("server %q has an empty %s health check", server.Name, healthCheckCommandPrefix))
+		}
+	}
+	for _, tool := range registry.Tools {
+		if tool.Name == "" {
+			problems = append(problems, fmt.Sprintf("tool on server %q has no name", tool.ServerName))
+		}
+	}
+	return problems
+}
+
+// duplicateEndpoints reports servers that share an identical endpoint, one
+// message per shared endpoint, in the order the endpoints first appear
+func duplicateEndpoints(servers []MCPServer) []string {
+	byEndpoint := make(map[string][]string)
+	var order []string
+	for _, server := range servers {
+		endpoint := strings.TrimSpace(server.Endpoint)
+		if endpoint == "" {
+			continue // reported as a missing endpoint instead
+		}
+		if _, ok := byEndpoint[endpoint]; !ok {
+			order = append(order, endpoint)
+		}
+		byEndpoint[endpoint] = append(byEndpoint[endpoint], server.Name)
+	}
+
+	var problems []string
+	for _, endpoint := range order {
+		if names := byEndpoint[endpoint]; len(names) > 1 {
+			problems = append(problems, fmt.Sprintf("servers %s share endpoint %s", strings.Join(quoteAll(names), ", "), endpoint))
+		}
+	}
+	return problems
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return quoted
+}
+
+// warnDuplicateEndpoints logs a warning for each shared endpoint unless
+// --allow-duplicate-endpoints is set
+func warnDuplicateEndpoints(registry *MCPRegistry) {
+	if allowDuplicateEndpoints {
+		return
+	}
+	for _, problem := range duplicateEndpoints(registry.Servers) {
+		log.Warn("Duplicate endpoint in registry", "problem", problem)
+	}
+}
+
+// validateRegistryCommand checks a registry file (the configured one when path
+// is empty) and lists every problem found
+func validateRegistryCommand(path string) error {
+	var registry *MCPRegistry
+	var err error
+	if path == "" {
+		path = configFile
+		registry, err = loadMCPRegistry()
+	} else {
+		registry, err = readRegistryFile(path)
+	}
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	problems := validateMCPRegistry(registry)
+	if !allowDuplicateEndpoints {
+		problems = append(problems, duplicateEndpoints(registry.Servers)...)
+	}
+
+	if len(problems) > 0 {
+		printResult("❌ %s has %d problems:\n", path, len(problems))
+		for _, problem := range problems {
+			printResult("   • %s\n", problem)
+		}
+		return validationError("%s failed validation", path)
+	}
+	printResult("✅ %s is valid (%d servers, %d tools)\n", path, len(registry.Servers), len(registry.Tools))
+	return nil
+}
+
+// diffRegistryFiles prints a unified diff between two registries in their
+// canonical JSON form. An empty oldPath means the registry from --config.
+func diffRegistryFiles(oldPath, newPath string, exitCode bool) error {
+	var oldRegistry *MCPRegistry
+	var err error
+	if oldPath == "" {
+		oldPath = configFile
+		oldRegistry, err = loadMCPRegistry()
+	} else {
+		oldRegistry, err = readRegistryFile(oldPath)
+	}
+	if err != nil {
+		return registryLoadError(err)
+	}
+	newRegistry, err := readRegistryFile(newPath)
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	oldJSON, err := json.MarshalIndent(oldRegistry, "", "  ")
+	if err != nil {
+		return err
+	}
+	newJSON, err := json.MarshalIndent(newRegistry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	diff := renderUnifiedDiff(oldPath, newPath, string(oldJSON), string(newJSON), useDiffColor())
+	if diff == "" {
+		printProgress("✅ %s and %s are identical\n", oldPath, newPath)
+		return nil
+	}
+	printResult("%s", diff)
+	if exitCode {
+		return withExitCode(exitFailure, fmt.Errorf("%s and %s differ", oldPath, newPath))
+	}
+	return nil
+}
+
+// renameRegistryServer renames a server and rewrites the ServerName of its tools
+func renameRegistryServer(oldName, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return usageError("new server name must not be empty")
+	}
+	if oldName == newName {
+		return usageError("old and new names are the same")
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	index := -1
+	for i, server := range registry.Servers {
+		switch server.Name {
+		case oldName:
+			index = i
+		case newName:
+			return validationError("a server named %s already exists", newName)
+		}
+	}
+	if index < 0 {
+		return notFoundError("server not found: %s", oldName)
+	}
+
+	registry.Servers[index].Name = newName
+	tools := 0
+	for i := range registry.Tools {
+		if registry.Tools[i].ServerName == oldName {
+			registry.Tools[i].ServerName = newName
+			tools++
+		}
+	}
+
+	renameServerSource(oldName, newName)
+	if err := saveMCPRegistry(registry); err != nil {
+		return fmt.Errorf("failed to save registry: %v", err)
+	}
+	logRegistryEvent("rename", oldName, oldName, newName)
+
+	printResult("✅ Renamed %s to %s (%d tools updated)\n", oldName, newName, tools)
+	return nil
+}
+
+// importRegistry merges servers from another registry file into the active registry.
+// strategy is one of skip, overwrite or merge and decides what happens on name conflicts.
+func importRegistry(path, strategy string) error {
+	if strategy != "skip" && strategy != "overwrite" && strategy != "merge" {
+		return usageError("invalid strategy %q (expected skip, overwrite or merge)", strategy)
+	}
+
+	incoming, err := readRegistryFile(path)
+	if err != nil {
+		return err
+	}
+	if problems := validateMCPRegistry(incoming); len(problems) > 0 {
+		printResult("❌ %s failed validation:\n", path)
+		for _, problem := range problems {
+			printResult("   • %s\n", problem)
+		}
+		return validationError("refusing to import invalid registry %s", path)
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	index := make(map[string]int)
+	for i, server := range registry.Servers {
+		index[server.Name] = i
+	}
+
+	incomingTools := make(map[string][]MCPTool)
+	for _, tool := range incoming.Tools {
+		incomingTools[tool.ServerName] = append(incomingTools[tool.ServerName], tool)
+	}
+
+	added, updated, skipped := 0, 0, 0
+	for _, server := range incoming.Servers {
+		i, exists := index[server.Name]
+		switch {
+		case !exists:
+			registry.Servers = append(registry.Servers, server)
+			registry.Tools = append(registry.Tools, incomingTools[server.Name]...)
+			index[server.Name] = len(registry.Servers) - 1
+			added++
+		case strategy == "skip":
+			skipped++
+		case strategy == "overwrite":
+			registry.Servers[i] = server
+			registry.Tools = replaceServerTools(registry.Tools, server.Name, incomingTools[server.Name])
+			updated++
+		case strategy == "merge":
+			registry.Servers[i] = mergeServers(registry.Servers[i], server)
+			registry.Tools = unionServerTools(registry.Tools, incomingTools[server.Name])
+			updated++
+		}
+	}
+
+	printProgress("📥 Import from %s (strategy: %s)\n", path, strategy)
+	printResult("   Added:   %d\n", added)
+	printResult("   Updated: %d\n", updated)
+	printResult("   Skipped: %d\n", skipped)
+
+	if added == 0 && updated == 0 {
+		printResult("✅ Nothing to import\n")
+		return nil
+	}
+
+	if err := saveMCPRegistry(registry); err != nil {
+		return err
+	}
+	printResult("✅ Registry saved to %s\n", configFile)
+	return nil
+}
+
+// mergeServers unions the tool lists and keeps the most recent LastSeen
+func mergeServers(existing, incoming MCPServer) MCPServer {
+	merged := existing
+
+	known := make(map[string]bool)
+	for _, tool := range existing.Tools {
+		known[tool] = true
+	}
+	for _, tool := range incoming.Tools {
+		if !known[tool] {
+			merged.Tools = append(merged.Tools, tool)
+			known[tool] = true
+		}
+	}
+
+	if incoming.LastSeen != nil {
+		incomingSeen, ok := parseRegistryTime(*incoming.LastSeen)
+		if ok {
+			if existing.LastSeen == nil {
+				merged.LastSeen = incoming.LastSeen
+			} else if existingSeen, ok := parseRegistryTime(*existing.LastSeen); !ok || incomingSeen.After(existingSeen) {
+				merged.LastSeen = incoming.LastSeen
+			}
+		}
+	}
+
+	return merged
+}
+
+// replaceServerTools swaps all tool entries of a server for a new set
+func replaceServerTools(tools []MCPTool, serverName string, replacement []MCPTool) []MCPTool {
+	result := []MCPTool{}
+	for _, tool := range tools {
+		if tool.ServerName != serverName {
+			result = append(result, tool)
+		}
+	}
+	return append(result, replacement...)
+}
+
+// unionServerTools adds tool entries that are not already present for their server
+func unionServerTools(tools []MCPTool, additions []MCPTool) []MCPTool {
+	known := make(map[string]bool)
+	for _, tool := range tools {
+		known[tool.ServerName+"/"+tool.Name] = true
+	}
+	for _, tool := range additions {
+		key := tool.ServerName + "/" + tool.Name
+		if !known[key] {
+			tools = append(tools, tool)
+			known[key] = true
+		}
+	}
+	return tools
+}
+
+// toolProvider is a server that exposes a given tool
+type toolProvider struct {
+	Server      string `json:"server"`
+	Status      string `json:"status"`
+	Endpoint    string `json:"endpoint"`
+	Description string `json:"description,omitempty"`
+}
+
+// findToolProviders looks a tool up in both MCPServer.Tools and the MCPTool server mapping
+func findToolProviders(registry *MCPRegistry, toolName string) []toolProvider {
+	descriptions := make(map[string]string)
+	mapped := make(map[string]bool)
+	for _, tool := range registry.Tools {
+		if tool.Name == toolName {
+			mapped[tool.ServerName] = true
+			descriptions[tool.ServerName] = tool.Description
+		}
+	}
+
+	var providers []toolProvider
+	for _, server := range registry.Servers {
+		exposes := mapped[server.Name]
+		for _, tool := range server.Tools {
+			if tool == toolName {
+				exposes = true
+				break
+			}
+		}
+		if exposes {
+			providers = append(providers, toolProvider{
+				Server:      server.Name,
+				Status:      server.Status,
+				Endpoint:    server.Endpoint,
+				Description: descriptions[server.Name],
+			})
+		}
+	}
+	return providers
+}
+
+func whichTool(toolName string, asJSON bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	if len(registry.Servers) == 0 {
+		return notFoundError("%s (%s)", emptyRegistryMessage, configFile)
+	}
+
+	providers := findToolProviders(registry, toolName)
+
+	if asJSON {
+		if providers == nil {
+			providers = []toolProvider{}
+		}
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"tool":    toolName,
+			"servers": providers,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+	}
+
+	if len(providers) == 0 {
+		return notFoundError("no server exposes tool %q", toolName)
+	}
+	if asJSON {
+		return nil
+	}
+
+	printResult("🛠️  %s is provided by %d server(s):\n\n", headerStyle.Render(toolName), len(providers))
+	for _, provider := range providers {
+		style := statusStopped
+		if isActiveStatus(provider.Status) {
+			style = statusRunning
+		}
+		printResult("   • %s [%s]\n", provider.Server, style.Render(provider.Status))
+		if provider.Description != "" {
+			printResult("     %s\n", provider.Description)
+		}
+	}
+	return nil
+}
+
+// probeResult is the outcome of `registry probe`
+type probeResult struct {
+	Server    string `json:"server,omitempty"`
+	Endpoint  string `json:"endpoint"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// probeEndpointCommand probes a registered server, honoring its declared health
+// check, or an explicit endpoint
+func probeEndpointCommand(serverName, endpoint string, timeout time.Duration, asJSON bool) error {
+	var target *MCPServer
+	if serverName != "" {
+		registry, err := loadMCPRegistry()
+		if err != nil {
+			return registryLoadError(err)
+		}
+		for i := range registry.Servers {
+			if registry.Servers[i].Name == serverName {
+				target = &registry.Servers[i]
+				endpoint = target.Endpoint
+				break
+			}
+		}
+		if target == nil {
+			return notFoundError("server not found: %s", serverName)
+		}
+	}
+
+	// Reject unsupported schemes before doing any work
+	if _, err := endpointScheme(endpoint); err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	stopProbe := startTiming("probe " + endpoint)
+	start := time.Now()
+	var probeErr error
+	if target != nil {
+		probeErr = probeServer(*target, timeout)
+	} else {
+		probeErr = probeMCPEndpoint(endpoint, timeout)
+	}
+	stopProbe()
+	result := probeResult{
+		Server:    serverName,
+		Endpoint:  endpoint,
+		Reachable: probeErr == nil,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if probeErr != nil {
+		result.Error = probeErr.Error()
+	}
+
+	if serverName != "" {
+		if err := recordHealthCheck(serverName, probeErr); err != nil {
+			log.Warn("Failed to record health check", "server", serverName, "error", err)
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+	} else if probeErr == nil {
+		printResult("✅ %s is reachable (%dms)\n", endpoint, result.LatencyMs)
+	} else {
+		printResult("❌ %s is unreachable (%dms): %v\n", endpoint, result.LatencyMs, probeErr)
+	}
+
+	if probeErr != nil {
+		return fmt.Errorf("probe failed: %v", probeErr)
+	}
+	return nil
+}
+
+// recordHealthCheck stores the outcome of a connectivity check on a server
+func recordHealthCheck(serverName string, checkErr error) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return err
+	}
+
+	for i := range registry.Servers {
+		if registry.Servers[i].Name == serverName {
+			applyHealthCheck(&registry.Servers[i], checkErr)
+			return saveMCPRegistry(registry)
+		}
+	}
+	return fmt.Errorf("server not found: %s", serverName)
+}
+
+// applyHealthCheck updates a server's health fields: success updates LastSeen
+// and clears the failure count, failure increments it
+func applyHealthCheck(server *MCPServer, checkErr error) {
+	now := time.Now().Format(time.RFC3339)
+	server.LastHealthCheck = now
+	if checkErr == nil {
+		server.LastSeen = &now
+		server.HealthCheckFails = 0
+	} else {
+		server.HealthCheckFails++
+	}
+}
+
+// serverHealth is one row of `registry health` output
+type serverHealth struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoint"`
+	Status    string `json:"status"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Failures  int    `json:"health_check_failures"`
+}
+
+// healthPassOptions select and pace the servers a health pass probes
+type healthPassOptions struct {
+	timeout    time.Duration
+	includeAll bool
+	// parallel caps how many probes run at once; 0 probes every server at once
+	parallel int
+	// onProbed, when set, is called after each probe with the number of probes
+	// finished so far. Calls are serialized.
+	onProbed func(done, total int, result serverHealth)
+}
+
+// runHealthPass probes the selected servers concurrently, records the results in
+// the registry and saves it. It returns one result per probed server and the
+// number that were unhealthy.
+func runHealthPass(registry *MCPRegistry, opts healthPassOptions) ([]serverHealth, int) {
+	var indexes []int
+	for i, server := range registry.Servers {
+		if opts.includeAll || isActiveStatus(server.Status) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	parallel := opts.parallel
+	if parallel <= 0 || parallel > len(indexes) {
+		parallel = len(indexes)
+	}
+
+	// Probe concurrently; each goroutine owns one result slot
+	stopProbes := startTiming("health probes")
+	results := make([]serverHealth, len(indexes))
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, max(parallel, 1))
+	for slot, i := range indexes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(slot int, server MCPServer) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer startTiming("probe " + server.Name)()
+			start := time.Now()
+			probeErr := probeServer(server, opts.timeout)
+			results[slot] = serverHealth{
+				Name:      server.Name,
+				Endpoint:  server.Endpoint,
+				Status:    server.Status,
+				Healthy:   probeErr == nil,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if probeErr != nil {
+				results[slot].Error = probeErr.Error()
+			}
+
+			if opts.onProbed != nil {
+				progressMu.Lock()
+				done++
+				opts.onProbed(done, len(indexes), results[slot])
+				progressMu.Unlock()
+			}
+		}(slot, registry.Servers[i])
+	}
+	wg.Wait()
+	stopProbes()
+
+	unhealthy := 0
+	outcomes := make(map[string]error, len(indexes))
+	for slot, i := range indexes {
+		var probeErr error
+		if !results[slot].Healthy {
+			probeErr = fmt.Errorf("%s", results[slot].Error)
+			unhealthy++
+		}
+		applyHealthCheck(&registry.Servers[i], probeErr)
+		results[slot].Failures = registry.Servers[i].HealthCheckFails
+		outcomes[registry.Servers[i].Name] = probeErr
+	}
+	if len(indexes) > 0 {
+		failures, err := recordHealthResults(outcomes)
+		if err != nil {
+			log.Warn("Failed to record health checks", "error", err)
+		}
+		for slot, i := range indexes {
+			if count, ok := failures[results[slot].Name]; ok {
+				registry.Servers[i].HealthCheckFails = count
+				results[slot].Failures = count
+			}
+		}
+	}
+	return results, unhealthy
+}
+
+// recordHealthResults stores probe outcomes by server name. Probes can take a
+// while, so the registry is re-read first and only the health fields of the
+// probed servers are updated: toggles, renames and registrations saved by other
+// processes in the meantime are kept. It returns the new failure counts.
+func recordHealthResults(outcomes map[string]error) (map[string]int, error) {
+	registry, err := reloadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	failures := make(map[string]int, len(outcomes))
+	for i := range registry.Servers {
+		server := &registry.Servers[i]
+		probeErr, ok := outcomes[server.Name]
+		if !ok {
+			continue
+		}
+		applyHealthCheck(server, probeErr)
+		failures[server.Name] = server.HealthCheckFails
+	}
+	if len(failures) == 0 {
+		return failures, nil
+	}
+	return failures, saveMCPRegistry(registry)
+}
+
+// checkRegistryHealth probes every active server (or all servers with includeAll),
+// records the results in the registry, and reports them. The returned error
+// carries the exit code: 1 if any server is unhealthy, 2 if the registry can't be loaded.
+func checkRegistryHealth(opts healthPassOptions, asJSON bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	var results []serverHealth
+	var unhealthy int
+	if !asJSON && showHealthProgress() {
+		results, unhealthy = runHealthPassWithProgress(registry, opts)
+	} else {
+		results, unhealthy = runHealthPass(registry, opts)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"healthy":   len(results) - unhealthy,
+			"unhealthy": unhealthy,
+			"servers":   results,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+	} else {
+		headers := []string{"NAME", "HEALTH", "LATENCY", "FAILS", "DETAIL"}
+		cells := make([][]string, 0, len(results))
+		for _, result := range results {
+			health := "healthy"
+			if !result.Healthy {
+				health = "unhealthy"
+			}
+			cells = append(cells, []string{result.Name, health, fmt.Sprintf("%dms", result.LatencyMs), strconv.Itoa(result.Failures), result.Error})
+		}
+		if isTerminal(os.Stdout) {
+			printProgress("🏥 MCP Server Health (%d checked)\n\n", len(results))
+			printResult("%s\n", renderTable(headers, cells, -1))
+		} else {
+			printResult("%s", renderTSV(headers, cells))
+		}
+		printResult("%d/%d servers healthy\n", len(results)-unhealthy, len(results))
+	}
+
+	if unhealthy > 0 {
+		return withExitCode(exitFailure, fmt.Errorf("%d of %d servers unhealthy", unhealthy, len(results)))
+	}
+	return nil
+}
+
+// categoryCount is one line of `registry categories` output
+type categoryCount struct {
+	Category string `json:"category"`
+	Servers  int    `json:"servers"`
+	Active   int    `json:"active"`
+}
+
+// countCategories tallies servers per category, sorted by name
+func countCategories(servers []MCPServer) []categoryCount {
+	index := make(map[string]int)
+	counts := []categoryCount{}
+	for _, server := range servers {
+		category := serverCategory(server)
+		i, ok := index[category]
+		if !ok {
+			i = len(counts)
+			index[category] = i
+			counts = append(counts, categoryCount{Category: category})
+		}
+		counts[i].Servers++
+		if isActiveStatus(server.Status) {
+			counts[i].Active++
+		}
+	}
+	sort.Slice(counts, func(a, b int) bool {
+		return counts[a].Category < counts[b].Category
+	})
+	return counts
+}
+
+// listRegistryCategories prints every category in the registry file with server counts
+func listRegistryCategories(asJSON bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+	counts := countCategories(registry.Servers)
+
+	if asJSON {
+		data, err := json.MarshalIndent(counts, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	headers := []string{"CATEGORY", "SERVERS", "ACTIVE"}
+	cells := make([][]string, 0, len(counts))
+	for _, count := range counts {
+		cells = append(cells, []string{count.Category, strconv.Itoa(count.Servers), strconv.Itoa(count.Active)})
+	}
+
+	if !isTerminal(os.Stdout) {
+		printResult("%s", renderTSV(headers, cells))
+		return nil
+	}
+
+	printProgress("📂 MCP Server Categories (%d total)\n\n", len(counts))
+	printResult("%s\n", renderTable(headers, cells, -1))
+	return nil
+}
+
+// frameworkStats is one line of `registry stats --by-framework` output
+type frameworkStats struct {
+	Framework   string  `json:"framework"`
+	Servers     int     `json:"servers"`
+	Active      int     `json:"active"`
+	ActiveRatio float64 `json:"active_ratio"`
+	Tools       int     `json:"tools"`
+}
+
+// registryStats is the `registry stats` report; Frameworks is only set with --by-framework
+type registryStats struct {
+	Servers    int              `json:"servers"`
+	Active     int              `json:"active"`
+	Tools      int              `json:"tools"`
+	Frameworks []frameworkStats `json:"frameworks,omitempty"`
+}
+
+// serverFramework returns the framework key for a server
+func serverFramework(server MCPServer) string {
+	if server.Metadata.Framework == "" {
+		return "unknown"
+	}
+	return server.Metadata.Framework
+}
+
+// activeRatio returns active/total, or 0 for an empty group
+func activeRatio(active, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(active) / float64(total)
+}
+
+// countFrameworks tallies servers, active servers and tools per framework, largest first
+func countFrameworks(servers []MCPServer) []frameworkStats {
+	index := make(map[string]int)
+	stats := []frameworkStats{}
+	for _, server := range servers {
+		framework := serverFramework(server)
+		i, ok := index[framework]
+		if !ok {
+			i = len(stats)
+			index[framework] = i
+			stats = append(stats, frameworkStats{Framework: framework})
+		}
+		stats[i].Servers++
+		stats[i].Tools += len(server.Tools)
+		if isActiveStatus(server.Status) {
+			stats[i].Active++
+		}
+	}
+	for i := range stats {
+		stats[i].ActiveRatio = activeRatio(stats[i].Active, stats[i].Servers)
+	}
+	sort.Slice(stats, func(a, b int) bool {
+		if stats[a].Servers != stats[b].Servers {
+			return stats[a].Servers > stats[b].Servers
+		}
+		return stats[a].Framework < stats[b].Framework
+	})
+	return stats
+}
+
+// showRegistryStats prints registry totals, optionally broken down by framework
+func showRegistryStats(byFramework, asJSON bool) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	report := registryStats{Servers: len(registry.Servers)}
+	for _, server := range registry.Servers {
+		report.Tools += len(server.Tools)
+		if isActiveStatus(server.Status) {
+			report.Active++
+		}
+	}
+	if byFramework {
+		report.Frameworks = countFrameworks(registry.Servers)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	if !byFramework {
+		printProgress("📊 MCP Registry Stats\n\n")
+		printResult("Servers: %d\n", report.Servers)
+		printResult("Active:  %d (%.0f%%)\n", report.Active, 100*activeRatio(report.Active, report.Servers))
+		printResult("Tools:   %d\n", report.Tools)
+		return nil
+	}
+
+	headers := []string{"FRAMEWORK", "SERVERS", "ACTIVE", "ACTIVE %", "TOOLS"}
+	cells := make([][]string, 0, len(report.Frameworks))
+	for _, stats := range report.Frameworks {
+		cells = append(cells, []string{
+			stats.Framework,
+			strconv.Itoa(stats.Servers),
+			strconv.Itoa(stats.Active),
+			fmt.Sprintf("%.0f%%", 100*stats.ActiveRatio),
+			strconv.Itoa(stats.Tools),
+		})
+	}
+
+	if !isTerminal(os.Stdout) {
+		printResult("%s", renderTSV(headers, cells))
+		return nil
+	}
+
+	printProgress("📊 MCP Servers by Framework (%d servers, %d active, %d tools)\n\n", report.Servers, report.Active, report.Tools)
+	printResult("%s\n", renderTable(headers, cells, -1))
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecordHealthResultsKeepsConcurrentChanges(t *testing.T) {
+	useTestRegistry(t, v090Fixture)
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		t.Fatalf("loadMCPRegistry: %v", err)
+	}
+
+	// Another process toggles the server while probes are running
+	registry.Servers[0].Status = "inactive"
+	if err := saveMCPRegistry(registry); err != nil {
+		t.Fatalf("saveMCPRegistry: %v", err)
+	}
+
+	failures, err := recordHealthResults(map[string]error{
+		"legacy-mcp":  errors.New("connection refused"),
+		"removed-mcp": nil,
+	})
+	if err != nil {
+		t.Fatalf("recordHealthResults: %v", err)
+	}
+	if failures["legacy-mcp"] != 1 {
+		t.Errorf("failures = %v, want legacy-mcp: 1", failures)
+	}
+	if _, ok := failures["removed-mcp"]; ok {
+		t.Error("recorded a result for a server that is not in the registry")
+	}
+
+	saved, err := reloadRegistry()
+	if err != nil {
+		t.Fatalf("reloadRegistry: %v", err)
+	}
+	server := saved.Servers[0]
+	if server.Status != "inactive" {
+		t.Errorf("Status = %q, want the concurrent toggle to inactive to survive", server.Status)
+	}
+	if server.HealthCheckFails != 1 || server.LastHealthCheck == "" {
+		t.Errorf("health fields = %d, %q, want 1 and a timestamp", server.HealthCheckFails, server.LastHealthCheck)
+	}
+}
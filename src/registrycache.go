@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The registry is parsed once and served from memory for as long as the files
+// behind it keep the same size and modification time, so a long-running
+// process or a read-modify-write still sees changes saved by other processes.
+// Callers always get their own copy, so editing one without saving it never
+// leaks into later loads.
+var (
+	registryCacheMu     sync.Mutex
+	cachedRegistry      *MCPRegistry
+	cachedRegistryFiles string // configFile the cache was loaded from
+	cachedRegistryStamp string // registryFileStamp when the cache was filled
+)
+
+// registryFileStamp summarises the size and modification time of every file
+// named by configFile. It changes whenever one of them is written.
+func registryFileStamp() string {
+	paths := []string{configFile}
+	if isMultiRegistryConfig(configFile) {
+		expanded, err := expandRegistryPaths(configFile)
+		if err != nil {
+			return ""
+		}
+		paths = expanded
+	}
+
+	var stamp strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&stamp, "%s:missing;", path)
+			continue
+		}
+		fmt.Fprintf(&stamp, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp.String()
+}
+
+// loadMCPRegistry returns the registry, reading it from disk on first use,
+// after the cache was invalidated, or when the file changed since it was read
+func loadMCPRegistry() (*MCPRegistry, error) {
+	registryCacheMu.Lock()
+	defer registryCacheMu.Unlock()
+
+	if cachedRegistry != nil && cachedRegistryFiles == configFile && cachedRegistryStamp == registryFileStamp() {
+		return cloneRegistry(cachedRegistry), nil
+	}
+
+	// Stamp before reading so a write racing with the read forces another load
+	stamp := registryFileStamp()
+	registry, err := readMCPRegistry()
+	if err != nil {
+		return nil, err
+	}
+	warnDuplicateEndpoints(registry)
+
+	// readMCPRegistry may have resolved configFile to a discovered location
+	cachedRegistry = cloneRegistry(registry)
+	cachedRegistryFiles = configFile
+	cachedRegistryStamp = stamp
+	return registry, nil
+}
+
+// reloadRegistry discards the cache and reads the registry from disk again,
+// e.g. to pick up changes made by another process
+func reloadRegistry() (*MCPRegistry, error) {
+	invalidateRegistryCache()
+	return loadMCPRegistry()
+}
+
+// invalidateRegistryCache makes the next load read from disk
+func invalidateRegistryCache() {
+	registryCacheMu.Lock()
+	cachedRegistry = nil
+	registryCacheMu.Unlock()
+}
+
+// cacheSavedRegistry keeps the cache in step with what saveMCPRegistry wrote.
+// After a failed save the file contents are unknown, so the cache is dropped.
+func cacheSavedRegistry(registry *MCPRegistry, saveErr error) {
+	registryCacheMu.Lock()
+	defer registryCacheMu.Unlock()
+
+	if saveErr != nil || registrySaveTo != "" {
+		// With --save-to the files named by --config were not updated
+		cachedRegistry = nil
+		return
+	}
+	cachedRegistry = cloneRegistry(registry)
+	cachedRegistryFiles = configFile
+	cachedRegistryStamp = registryFileStamp()
+}
+
+// cloneRegistry returns a deep copy of a registry
+func cloneRegistry(registry *MCPRegistry) *MCPRegistry {
+	clone := *registry
+	clone.Servers = make([]MCPServer, len(registry.Servers))
+	for i, server := range registry.Servers {
+		if server.Tools != nil {
+			server.Tools = append([]string{}, server.Tools...)
+		}
+		if server.Metadata.EnvironmentVars != nil {
+			server.Metadata.EnvironmentVars = append([]string{}, server.Metadata.EnvironmentVars...)
+		}
+		if server.LastSeen != nil {
+			lastSeen := *server.LastSeen
+			server.LastSeen = &lastSeen
+		}
+		clone.Servers[i] = server
+	}
+	clone.Tools = append([]MCPTool{}, registry.Tools...)
+	return &clone
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSeesChangesFromOtherProcesses(t *testing.T) {
+	path := useTestRegistry(t, v090Fixture)
+	if _, err := loadMCPRegistry(); err != nil {
+		t.Fatalf("loadMCPRegistry: %v", err)
+	}
+
+	// Another process renames the server behind the cache's back
+	changed := strings.Replace(v090Fixture, "legacy-mcp", "renamed-mcp", 1)
+	if err := os.WriteFile(path, []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		t.Fatalf("loadMCPRegistry: %v", err)
+	}
+	if got := registry.Servers[0].Name; got != "renamed-mcp" {
+		t.Errorf("server name = %q after the file changed, want renamed-mcp", got)
+	}
+}
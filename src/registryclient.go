@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	// registryRequestTimeout bounds a whole registry request, retries included
+	registryRequestTimeout = 5 * time.Second
+	// registryRetries is how many times a failed idempotent request is repeated
+	registryRetries = 2
+)
+
+// registryTransport is shared by every registry client so connections to the
+// registry are kept alive and reused across calls within one process
+var registryTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   2 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	MaxIdleConns:        16,
+	MaxIdleConnsPerHost: 8,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// registryClient is the client for registry calls. Use registryClientWithTimeout
+// when a call needs a different deadline; it shares the same connection pool.
+var registryClient = registryClientWithTimeout(registryRequestTimeout)
+
+func registryClientWithTimeout(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{base: registryTransport, retries: registryRetries},
+		Timeout:   timeout,
+	}
+}
+
+// retryTransport repeats GET and HEAD requests that fail in a way a second
+// attempt can fix: a reused connection the registry already closed, or a
+// 502/503/504 while it restarts. Refused connections, DNS errors and timeouts
+// are returned at once so an unreachable registry is still reported quickly.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !isRetryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(time.Duration(attempt+1) * 100 * time.Millisecond):
+		}
+	}
+}
+
+// isRetryable reports whether a registry response or error is worth retrying
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return false
+		}
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
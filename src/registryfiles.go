@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// When several registry files are merged, remember what each file contained and
+// which file each server and tool came from so saves can write them back in place
+var (
+	registrySourcesMu   sync.Mutex
+	registryFileContent map[string]*MCPRegistry
+	serverSources       map[string]string
+	toolSources         map[string]string
+	registryPaths       []string
+)
+
+func toolKey(tool MCPTool) string {
+	return tool.ServerName + "/" + tool.Name
+}
+
+// isMultiRegistryConfig reports whether --config names several files or a glob
+func isMultiRegistryConfig(config string) bool {
+	return strings.Contains(config, ",") || strings.ContainsAny(config, "*?[")
+}
+
+// expandRegistryPaths turns a comma-separated list of files and globs into
+// concrete paths, in the order given. Globs expand in lexical order.
+func expandRegistryPaths(config string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(config, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		matches := []string{part}
+		if strings.ContainsAny(part, "*?[") {
+			var err error
+			matches, err = filepath.Glob(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid registry glob %q: %v", part, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no registry files match %q", part)
+			}
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no registry files given")
+	}
+	return paths, nil
+}
+
+// loadMergedRegistry loads every configured file and merges them into one registry.
+// Servers are deduplicated by name and tools by name and server; later files win.
+func loadMergedRegistry() (*MCPRegistry, error) {
+	paths, err := expandRegistryPaths(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &MCPRegistry{Version: currentRegistryVersion}
+	contents := make(map[string]*MCPRegistry, len(paths))
+	servers := make(map[string]string)
+	tools := make(map[string]string)
+	serverIndex := make(map[string]int)
+	toolIndex := make(map[string]int)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry file: %v", err)
+		}
+		registry, err := decodeRegistryFile(path, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		contents[path] = registry
+
+		if registry.Timestamp > merged.Timestamp {
+			merged.Timestamp = registry.Timestamp
+		}
+
+		for _, server := range registry.Servers {
+			if i, ok := serverIndex[server.Name]; ok {
+				merged.Servers[i] = server
+			} else {
+				serverIndex[server.Name] = len(merged.Servers)
+				merged.Servers = append(merged.Servers, server)
+			}
+			servers[server.Name] = path
+		}
+
+		for _, tool := range registry.Tools {
+			key := toolKey(tool)
+			if i, ok := toolIndex[key]; ok {
+				merged.Tools[i] = tool
+			} else {
+				toolIndex[key] = len(merged.Tools)
+				merged.Tools = append(merged.Tools, tool)
+			}
+			tools[key] = path
+		}
+	}
+	normalizeMCPRegistry(merged)
+
+	registrySourcesMu.Lock()
+	registryFileContent = contents
+	serverSources = servers
+	toolSources = tools
+	registryPaths = paths
+	registrySourcesMu.Unlock()
+
+	return merged, nil
+}
+
+// saveMergedRegistry writes a merged registry back to disk. With --save-to the
+// whole registry goes to that file. Otherwise each server and tool is written to
+// the file it was loaded from; copies shadowed by a later file are left as they
+// were unless the entry was removed, and new servers go to the first file (new
+// tools follow their server).
+func saveMergedRegistry(registry *MCPRegistry) error {
+	if registrySaveTo != "" {
+		return writeRegistryFile(registrySaveTo, registry)
+	}
+
+	registrySourcesMu.Lock()
+	defer registrySourcesMu.Unlock()
+
+	if len(registryPaths) == 0 {
+		return fmt.Errorf("registry files must be loaded before they can be saved")
+	}
+	primary := registryPaths[0]
+
+	current := make(map[string]MCPServer, len(registry.Servers))
+	for _, server := range registry.Servers {
+		current[server.Name] = server
+	}
+	currentTools := make(map[string]MCPTool, len(registry.Tools))
+	for _, tool := range registry.Tools {
+		currentTools[toolKey(tool)] = tool
+	}
+
+	parts := make(map[string]*MCPRegistry, len(registryPaths))
+	for _, path := range registryPaths {
+		part := &MCPRegistry{
+			Version:   registry.Version,
+			Timestamp: registry.Timestamp,
+			Servers:   []MCPServer{},
+			Tools:     []MCPTool{},
+		}
+		if original, ok := registryFileContent[path]; ok {
+			for _, server := range original.Servers {
+				updated, ok := current[server.Name]
+				if !ok {
+					continue // removed from the registry
+				}
+				if serverSources[server.Name] == path {
+					part.Servers = append(part.Servers, updated)
+				} else {
+					part.Servers = append(part.Servers, server)
+				}
+			}
+			for _, tool := range original.Tools {
+				key := toolKey(tool)
+				updated, ok := currentTools[key]
+				if !ok {
+					continue
+				}
+				if toolSources[key] == path {
+					part.Tools = append(part.Tools, updated)
+				} else {
+					part.Tools = append(part.Tools, tool)
+				}
+			}
+		}
+		parts[path] = part
+	}
+
+	for _, server := range registry.Servers {
+		if _, ok := serverSources[server.Name]; !ok {
+			parts[primary].Servers = append(parts[primary].Servers, server)
+			serverSources[server.Name] = primary
+		}
+	}
+	for _, tool := range registry.Tools {
+		key := toolKey(tool)
+		if _, ok := toolSources[key]; ok {
+			continue
+		}
+		path, ok := serverSources[tool.ServerName]
+		if !ok {
+			path = primary
+		}
+		parts[path].Tools = append(parts[path].Tools, tool)
+		toolSources[key] = path
+	}
+
+	for _, path := range registryPaths {
+		if err := writeRegistryFile(path, parts[path]); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		registryFileContent[path] = parts[path]
+	}
+	return nil
+}
+
+// renameServerSource carries a server rename into the per-file bookkeeping so a
+// merged save writes the renamed server and its tools back to their own file
+func renameServerSource(oldName, newName string) {
+	registrySourcesMu.Lock()
+	defer registrySourcesMu.Unlock()
+
+	for _, content := range registryFileContent {
+		for i := range content.Servers {
+			if content.Servers[i].Name == oldName {
+				content.Servers[i].Name = newName
+			}
+		}
+		for i := range content.Tools {
+			if content.Tools[i].ServerName == oldName {
+				content.Tools[i].ServerName = newName
+			}
+		}
+	}
+
+	if path, ok := serverSources[oldName]; ok {
+		delete(serverSources, oldName)
+		serverSources[newName] = path
+	}
+	moved := make(map[string]string)
+	for key, path := range toolSources {
+		if strings.HasPrefix(key, oldName+"/") {
+			delete(toolSources, key)
+			moved[newName+strings.TrimPrefix(key, oldName)] = path
+		}
+	}
+	for key, path := range moved {
+		toolSources[key] = path
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// secretMask replaces any value that looks like a credential
+const secretMask = "****"
+
+// secretNamePattern matches names that hold credentials: snake, kebab and
+// camel case names ending in a credential word, or the bare words themselves
+const secretNamePattern = `[A-Z0-9_-]*[_-](?:TOKEN|KEY|SECRET|PASSWORD)|(?-i:[a-z][a-zA-Z0-9]*(?:Token|Key|Secret|Password))|TOKEN|SECRET|PASSWORD|APIKEY`
+
+var (
+	// NAME=value or NAME: value where NAME looks like a credential (GITHUB_TOKEN,
+	// api-key, accessToken, ...). An optional closing quote before the separator
+	// covers JSON and quoted keys such as {"api_key":"abc"}.
+	secretAssignPattern = regexp.MustCompile(`(?i)\b(` + secretNamePattern + `)(["']?\s*[=:]\s*)("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,;&"'{\[]+)`)
+
+	// --name value, for flags that take a credential as their next argument
+	secretFlagPattern = regexp.MustCompile(`(?i)(--?(?:` + secretNamePattern + `))(\s+)([^\s-]\S*)`)
+
+	// Authorization: Bearer <token>
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`)
+
+	// Extra patterns and key names from --redact
+	redactPatterns []*regexp.Regexp
+	redactKeys     map[string]bool
+)
+
+// compileRedactPatterns prepares the --redact values. Each value is matched
+// against field names exactly and also used as a regular expression on text.
+func compileRedactPatterns(values []string) error {
+	redactPatterns = nil
+	redactKeys = make(map[string]bool)
+	for _, value := range values {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return usageError("invalid --redact pattern %q: %v", value, err)
+		}
+		redactPatterns = append(redactPatterns, pattern)
+		redactKeys[strings.ToUpper(value)] = true
+	}
+	return nil
+}
+
+// isSecretKey reports whether a field name should have its value masked.
+// Names are compared in upper snake case, so api-key and apiKey match API_KEY.
+func isSecretKey(key string) bool {
+	if redactKeys[strings.ToUpper(key)] {
+		return true
+	}
+	upper := secretKeyName(key)
+	for _, suffix := range []string{"_TOKEN", "_KEY", "_SECRET", "PASSWORD"} {
+		if strings.HasSuffix(upper, suffix) {
+			return true
+		}
+	}
+	switch upper {
+	case "TOKEN", "SECRET", "APIKEY", "AUTHORIZATION":
+		return true
+	}
+	return false
+}
+
+// secretKeyName converts a kebab or camel case field name to upper snake case
+func secretKeyName(key string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range key {
+		if r == '-' {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// maskSecrets masks credential-looking values inside free text such as command
+// lines and log messages
+func maskSecrets(text string) string {
+	text = secretAssignPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := secretAssignPattern.FindStringSubmatch(match)
+		// Keep quotes around a masked value so masked JSON stays valid
+		quote := ""
+		if value := parts[3]; len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			quote = value[:1]
+		}
+		return parts[1] + parts[2] + quote + secretMask + quote
+	})
+	text = secretFlagPattern.ReplaceAllString(text, "${1}${2}"+secretMask)
+	text = bearerPattern.ReplaceAllString(text, "${1} "+secretMask)
+	for _, pattern := range redactPatterns {
+		text = pattern.ReplaceAllString(text, secretMask)
+	}
+	return text
+}
+
+// maskFields returns a copy of structured log fields with secret keys masked
+// and string values scrubbed
+func maskFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if isSecretKey(key) {
+			masked[key] = secretMask
+			continue
+		}
+		if text, ok := value.(string); ok {
+			masked[key] = maskSecrets(text)
+			continue
+		}
+		masked[key] = value
+	}
+	return masked
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestMaskSecrets(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"devgen --auth-token=abc123 status", "devgen --auth-token=**** status"},
+		{"password=hunter2", "password=****"},
+		{"GITHUB_TOKEN=ghp_abc make", "GITHUB_TOKEN=**** make"},
+		{`PASSWORD="two words"`, `PASSWORD="****"`},
+		{`{"api_key":"abc"}`, `{"api_key":"****"}`},
+		{`{"access_token": "a\"b", "name": "x"}`, `{"access_token": "****", "name": "x"}`},
+		{`{"accessToken":"abc","count":2}`, `{"accessToken":"****","count":2}`},
+		{`{'api_key': 'abc'}`, `{'api_key': '****'}`},
+		{"--api-key=abc123", "--api-key=****"},
+		{"X-Api-Key: abc", "X-Api-Key: ****"},
+		{"devgen --token abc status", "devgen --token **** status"},
+		{"call --api-key abc123 --json", "call --api-key **** --json"},
+		{"--token --json", "--token --json"},
+		{"Authorization: Bearer abc.def", "Authorization: Bearer ****"},
+		{"monkey=banana keyboard: qwerty", "monkey=banana keyboard: qwerty"},
+	}
+	for _, tt := range tests {
+		if got := maskSecrets(tt.in); got != tt.want {
+			t.Errorf("maskSecrets(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"GITHUB_TOKEN", true},
+		{"api_key", true},
+		{"api-key", true},
+		{"apiKey", true},
+		{"accessToken", true},
+		{"X-Api-Key", true},
+		{"password", true},
+		{"Authorization", true},
+		{"name", false},
+		{"monkey", false},
+		{"tokens", false},
+	}
+	for _, tt := range tests {
+		if got := isSecretKey(tt.key); got != tt.want {
+			t.Errorf("isSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
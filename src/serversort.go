@@ -0,0 +1,96 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// serverSortKeys are the accepted --sort values, in the order the dashboard cycles them
+var serverSortKeys = []string{"name", "status", "tools", "last-seen"}
+
+// serverSortFields are the values a server listing can be ordered by. Both
+// registry rows and local registry records are reduced to these so the CLI and
+// the dashboard share one comparator.
+type serverSortFields struct {
+	name     string
+	status   string
+	tools    int
+	lastSeen string
+}
+
+func rowSortFields(row registryServerRow) serverSortFields {
+	return serverSortFields{name: row.Name, status: row.Status, tools: row.Tools, lastSeen: row.LastSeen}
+}
+
+func mcpServerSortFields(server MCPServer) serverSortFields {
+	fields := serverSortFields{name: server.Name, status: server.Status, tools: len(server.Tools)}
+	if server.LastSeen != nil {
+		fields.lastSeen = *server.LastSeen
+	}
+	return fields
+}
+
+// validateSortKey rejects unknown --sort values
+func validateSortKey(key string) error {
+	for _, valid := range serverSortKeys {
+		if key == valid {
+			return nil
+		}
+	}
+	return usageError("invalid --sort value %q (expected %s)", key, strings.Join(serverSortKeys, ", "))
+}
+
+// compareServers orders two servers by key. Names and statuses sort A–Z; tools
+// sort most first and last-seen most recent first, with never-seen servers at
+// the end. Ties fall back to the name so the order is stable across runs.
+func compareServers(key string, a, b serverSortFields) int {
+	switch key {
+	case "status":
+		if c := strings.Compare(strings.ToLower(a.status), strings.ToLower(b.status)); c != 0 {
+			return c
+		}
+	case "tools":
+		if a.tools != b.tools {
+			if a.tools > b.tools {
+				return -1
+			}
+			return 1
+		}
+	case "last-seen":
+		at, aok := parseRegistryTime(a.lastSeen)
+		bt, bok := parseRegistryTime(b.lastSeen)
+		switch {
+		case aok && !bok:
+			return -1
+		case !aok && bok:
+			return 1
+		case aok && bok && !at.Equal(bt):
+			return bt.Compare(at)
+		}
+	}
+	return strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+}
+
+// sortServerRows orders `registry servers` output by key, reversed when asked
+func sortServerRows(rows []registryServerRow, key string, reverse bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		c := compareServers(key, rowSortFields(rows[i]), rowSortFields(rows[j]))
+		if reverse {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// nextSortKey returns the dashboard sort after key; "" keeps registry order
+func nextSortKey(key string) string {
+	for i, candidate := range serverSortKeys {
+		if candidate == key {
+			if i+1 < len(serverSortKeys) {
+				return serverSortKeys[i+1]
+			}
+			return ""
+		}
+	}
+	return serverSortKeys[0]
+}
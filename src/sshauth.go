@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+)
+
+// Authenticator decides whether an SSH login is allowed. The server wraps it with
+// rate limiting and audit logging, so implementations only answer yes or no.
+type Authenticator interface {
+	AuthPassword(ctx ssh.Context, password string) bool
+	AuthPublicKey(ctx ssh.Context, key ssh.PublicKey) bool
+	// Describe is shown at startup so operators can see how logins are checked
+	Describe() string
+}
+
+// sshAuthBackends lists the values accepted by --ssh-auth
+var sshAuthBackends = []string{"demo", "password", "authorized-keys", "deny-all"}
+
+// newAuthenticator builds the backend selected with --ssh-auth
+func newAuthenticator(backend string) (Authenticator, error) {
+	switch backend {
+	case "demo":
+		return demoAuthenticator{staticPasswordAuthenticator{passwords: []string{"demo", "devq"}}}, nil
+	case "password":
+		password := os.Getenv("DEVGEN_SSH_PASSWORD")
+		if password == "" {
+			return nil, fmt.Errorf("--ssh-auth password requires DEVGEN_SSH_PASSWORD to be set")
+		}
+		return staticPasswordAuthenticator{passwords: []string{password}}, nil
+	case "authorized-keys":
+		return loadAuthorizedKeys(authorizedKeysPath())
+	case "deny-all":
+		return denyAllAuthenticator{}, nil
+	default:
+		return nil, usageError("invalid --ssh-auth value %q (expected %s)", backend, strings.Join(sshAuthBackends, ", "))
+	}
+}
+
+// staticPasswordAuthenticator accepts any of a fixed set of passwords and no keys
+type staticPasswordAuthenticator struct {
+	passwords []string
+}
+
+func (a staticPasswordAuthenticator) AuthPassword(ctx ssh.Context, password string) bool {
+	for _, candidate := range a.passwords {
+		if subtle.ConstantTimeCompare([]byte(password), []byte(candidate)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (a staticPasswordAuthenticator) AuthPublicKey(ctx ssh.Context, key ssh.PublicKey) bool {
+	return false
+}
+
+func (a staticPasswordAuthenticator) Describe() string {
+	return "password (DEVGEN_SSH_PASSWORD)"
+}
+
+// demoAuthenticator is the historical default: well-known passwords and any public key.
+// It is meant for local demos only.
+type demoAuthenticator struct {
+	staticPasswordAuthenticator
+}
+
+func (a demoAuthenticator) AuthPublicKey(ctx ssh.Context, key ssh.PublicKey) bool {
+	return true
+}
+
+func (a demoAuthenticator) Describe() string {
+	return "demo (password: demo or devq, any public key)"
+}
+
+// authorizedKeysAuthenticator accepts public keys listed in an authorized_keys file
+type authorizedKeysAuthenticator struct {
+	path string
+	keys []ssh.PublicKey
+}
+
+// authorizedKeysPath returns --ssh-authorized-keys or ~/.ssh/authorized_keys
+func authorizedKeysPath() string {
+	if sshAuthorizedKeys != "" {
+		return sshAuthorizedKeys
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".ssh", "authorized_keys")
+	}
+	return filepath.Join(home, ".ssh", "authorized_keys")
+}
+
+// loadAuthorizedKeys parses an authorized_keys file, skipping blank lines and comments
+func loadAuthorizedKeys(path string) (*authorizedKeysAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized keys: %v", err)
+	}
+
+	auth := &authorizedKeysAuthenticator{path: path}
+	for lineNum, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid key: %v", path, lineNum+1, err)
+		}
+		auth.keys = append(auth.keys, key)
+	}
+	if len(auth.keys) == 0 {
+		return nil, fmt.Errorf("%s contains no keys", path)
+	}
+	return auth, nil
+}
+
+func (a *authorizedKeysAuthenticator) AuthPassword(ctx ssh.Context, password string) bool {
+	return false
+}
+
+func (a *authorizedKeysAuthenticator) AuthPublicKey(ctx ssh.Context, key ssh.PublicKey) bool {
+	for _, allowed := range a.keys {
+		if ssh.KeysEqual(key, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *authorizedKeysAuthenticator) Describe() string {
+	return fmt.Sprintf("authorized keys (%d keys from %s)", len(a.keys), a.path)
+}
+
+// denyAllAuthenticator rejects every login, e.g. to take a server offline without stopping it
+type denyAllAuthenticator struct{}
+
+func (denyAllAuthenticator) AuthPassword(ctx ssh.Context, password string) bool {
+	return false
+}
+
+func (denyAllAuthenticator) AuthPublicKey(ctx ssh.Context, key ssh.PublicKey) bool {
+	return false
+}
+
+func (denyAllAuthenticator) Describe() string {
+	return "deny-all (every login is rejected)"
+}
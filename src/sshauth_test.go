@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+)
+
+const (
+	trustedKeyLine   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFKlCUakCXHWQl78/RWu2KrE21xMFWSCBIl+c8tK/iZG a@test"
+	untrustedKeyLine = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMzCSY7qM/4OKIumPHkrkRHm2QN+cPk2eof7kDHNyAQ7 b@test"
+)
+
+func parseTestKey(t *testing.T, line string) ssh.PublicKey {
+	t.Helper()
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// writeAuthorizedKeys points --ssh-authorized-keys at a temporary file with contents
+func writeAuthorizedKeys(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := sshAuthorizedKeys
+	sshAuthorizedKeys = path
+	t.Cleanup(func() { sshAuthorizedKeys = old })
+	return path
+}
+
+func TestAuthenticatorBackends(t *testing.T) {
+	t.Setenv("DEVGEN_SSH_PASSWORD", "s3cret")
+	writeAuthorizedKeys(t, "# devgen operators\n\n"+trustedKeyLine+"\n")
+	trusted := parseTestKey(t, trustedKeyLine)
+	untrusted := parseTestKey(t, untrustedKeyLine)
+
+	tests := []struct {
+		backend  string
+		password string
+		key      ssh.PublicKey
+		want     bool
+	}{
+		{"demo", "demo", nil, true},
+		{"demo", "devq", nil, true},
+		{"demo", "s3cret", nil, false},
+		{"demo", "", untrusted, true},
+		{"password", "s3cret", nil, true},
+		{"password", "demo", nil, false},
+		{"password", "", nil, false},
+		{"password", "", trusted, false},
+		{"authorized-keys", "", trusted, true},
+		{"authorized-keys", "", untrusted, false},
+		{"authorized-keys", "demo", nil, false},
+		{"deny-all", "demo", nil, false},
+		{"deny-all", "", trusted, false},
+	}
+	for _, tt := range tests {
+		auth, err := newAuthenticator(tt.backend)
+		if err != nil {
+			t.Fatalf("newAuthenticator(%q): %v", tt.backend, err)
+		}
+		var got bool
+		if tt.key != nil {
+			got = auth.AuthPublicKey(nil, tt.key)
+		} else {
+			got = auth.AuthPassword(nil, tt.password)
+		}
+		if got != tt.want {
+			t.Errorf("%s: password %q, key %v: got %v, want %v", tt.backend, tt.password, tt.key != nil, got, tt.want)
+		}
+	}
+}
+
+func TestPasswordBackendRequiresPassword(t *testing.T) {
+	t.Setenv("DEVGEN_SSH_PASSWORD", "")
+	if _, err := newAuthenticator("password"); err == nil {
+		t.Error("password backend accepted an empty DEVGEN_SSH_PASSWORD")
+	}
+}
+
+func TestAuthorizedKeysErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		missing  bool
+		wantErr  string
+	}{
+		{name: "missing file", missing: true, wantErr: "failed to read authorized keys"},
+		{name: "malformed key", contents: trustedKeyLine + "\nssh-ed25519 not-base64\n", wantErr: ":2: invalid key"},
+		{name: "comments only", contents: "# nobody yet\n\n", wantErr: "contains no keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeAuthorizedKeys(t, tt.contents)
+			if tt.missing {
+				os.Remove(path)
+			}
+			_, err := newAuthenticator("authorized-keys")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnknownAuthBackend(t *testing.T) {
+	_, err := newAuthenticator("kerberos")
+	if err == nil {
+		t.Fatal("unknown backend accepted")
+	}
+	if code := exitCode(err); code != exitUsage {
+		t.Errorf("exit code = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(err.Error(), "kerberos") {
+		t.Errorf("error %q does not name the backend", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// isLoopbackHost reports whether the SSH server would only be reachable locally
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isWritableDir reports whether files can be created in dir
+func isWritableDir(dir string) bool {
+	f, err := os.CreateTemp(dir, ".devgen-check-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// checkHostKey verifies the host key parses, or that it could be generated
+func checkHostKey() doctorCheck {
+	check := doctorCheck{Name: "Host key"}
+	path := filepath.Join(sshKeyDir, sshHostKeyName)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		dir := sshKeyDir
+		if _, err := os.Stat(dir); err != nil {
+			dir = "."
+		}
+		if !isWritableDir(dir) {
+			check.Status = doctorFail
+			check.Detail = fmt.Sprintf("%s is missing and %s is not writable", path, dir)
+			return check
+		}
+		check.Status = doctorPass
+		check.Detail = fmt.Sprintf("%s will be generated on first start", path)
+		return check
+	}
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return check
+	}
+
+	// Accepts the OpenSSH format the server generates as well as PEM keys
+	if _, err := gossh.ParsePrivateKey(data); err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s could not be parsed: %v", path, err)
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = path
+	return check
+}
+
+// checkSSHAuth verifies the selected backend can be built. The demo backend is
+// a warning on loopback and a failure when the server would be reachable remotely.
+func checkSSHAuth() doctorCheck {
+	check := doctorCheck{Name: "Authentication"}
+	if sshAuthBackend == "authorized-keys" {
+		// Reported by checkAuthorizedKeys
+		check.Status = doctorPass
+		check.Detail = "authorized-keys"
+		return check
+	}
+
+	auth, err := newAuthenticator(sshAuthBackend)
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return check
+	}
+	check.Detail = auth.Describe()
+	switch sshAuthBackend {
+	case "demo":
+		check.Status = doctorWarn
+		if !isLoopbackHost(sshHost) {
+			check.Status = doctorFail
+		}
+		check.Detail += "; choose --ssh-auth password or authorized-keys before exposing the server"
+		if !sshReadOnlyMode() {
+			check.Detail += " (--ssh-allow-toggle lets any client change the registry)"
+		}
+	case "deny-all":
+		check.Status = doctorWarn
+	default:
+		check.Status = doctorPass
+	}
+	return check
+}
+
+// checkAuthorizedKeys verifies the authorized_keys file parses
+func checkAuthorizedKeys() doctorCheck {
+	check := doctorCheck{Name: "Authorized keys"}
+	auth, err := loadAuthorizedKeys(authorizedKeysPath())
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return check
+	}
+	check.Status = doctorPass
+	check.Detail = fmt.Sprintf("%d keys in %s", len(auth.keys), auth.path)
+	return check
+}
+
+// checkSSHBind verifies the listen address is free
+func checkSSHBind() doctorCheck {
+	check := checkSSHPort()
+	check.Name = "Bind address"
+	if check.Status == doctorWarn {
+		check.Status = doctorFail
+	}
+	return check
+}
+
+// runSSHCheck prints a readiness report for the SSH server without listening.
+// It returns an error when any check fails.
+func runSSHCheck() error {
+	checks := []doctorCheck{
+		checkRegistryFile(),
+		checkHostKey(),
+		checkSSHAuth(),
+	}
+	if sshAuthBackend == "authorized-keys" {
+		checks = append(checks, checkAuthorizedKeys())
+	}
+	checks = append(checks, checkSSHBind())
+
+	printProgress("🔐 SSH readiness for %s:%d\n\n", sshHost, sshPort)
+	var failed, warned int
+	for _, check := range checks {
+		printResult("%s", renderDoctorCheck(check))
+		switch check.Status {
+		case doctorWarn:
+			warned++
+		case doctorFail:
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		printResult("\n❌ Not ready: %d failed, %d warnings\n", failed, warned)
+		return fmt.Errorf("ssh check found %d failing checks", failed)
+	}
+	printResult("\n✅ Ready to start (%d warnings)\n", warned)
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// sshShellCommands are completed at the start of an interactive SSH line
+var sshShellCommands = []string{"list", "status", "toggle", "health", "help", "exit"}
+
+// sshServerArgCommands take a server name as their argument
+var sshServerArgCommands = map[string]bool{
+	"status": true,
+	"toggle": true,
+}
+
+// sshCompletionOptions returns what the word being typed can complete to:
+// command names for the first word, and server names after status or toggle.
+// Commands disabled in read-only mode are not offered.
+func sshCompletionOptions(before []string, registry *MCPRegistry) []string {
+	var options []string
+	switch {
+	case len(before) == 0:
+		for _, command := range sshShellCommands {
+			if !(sshReadOnlyMode() && sshMutatingCommands[command]) {
+				options = append(options, command)
+			}
+		}
+	case len(before) == 1 && sshServerArgCommands[before[0]]:
+		if sshReadOnlyMode() && sshMutatingCommands[before[0]] {
+			return nil
+		}
+		if registry != nil {
+			for _, server := range registry.Servers {
+				options = append(options, server.Name)
+			}
+		}
+	}
+	return options
+}
+
+// completeSSHLine completes the word before the cursor. A single match is
+// filled in followed by a space; several matches are extended to their
+// longest common prefix, and when that adds nothing they are returned as
+// candidates to list. Matching ignores case.
+func completeSSHLine(line string, pos int, registry *MCPRegistry) (newLine string, newPos int, candidates []string) {
+	head, tail := line[:pos], line[pos:]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := strings.ToLower(head[start:])
+
+	var matches []string
+	for _, option := range sshCompletionOptions(strings.Fields(head[:start]), registry) {
+		if strings.HasPrefix(strings.ToLower(option), word) {
+			matches = append(matches, option)
+		}
+	}
+	sort.Strings(matches)
+
+	completion := ""
+	switch len(matches) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		completion = matches[0]
+		if !strings.HasPrefix(tail, " ") {
+			completion += " "
+		}
+	default:
+		completion = commonPrefix(matches)
+		if len(completion) <= len(word) {
+			return line, pos, matches
+		}
+	}
+	return head[:start] + completion + tail, start + len(completion), nil
+}
+
+// commonPrefix returns the longest prefix shared by every string
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// timingSpan is one measured phase of a command
+type timingSpan struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+var (
+	timingsMu    sync.Mutex
+	timingSpans  []timingSpan
+	commandStart = time.Now()
+)
+
+// startTiming begins a span and returns the function that ends it. Use it as
+// `defer startTiming("registry load")()`. Spans are only recorded with --timings.
+func startTiming(name string) func() {
+	if !showTimings {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		timingsMu.Lock()
+		timingSpans = append(timingSpans, timingSpan{Name: name, Start: start, Duration: time.Since(start)})
+		timingsMu.Unlock()
+	}
+}
+
+// formatElapsed renders a duration with a precision suited to its size
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// printTimings writes the recorded spans to stderr, in the order they started,
+// so --json output on stdout stays machine readable
+func printTimings() {
+	if !showTimings {
+		return
+	}
+	timingsMu.Lock()
+	spans := make([]timingSpan, len(timingSpans))
+	copy(spans, timingSpans)
+	timingsMu.Unlock()
+
+	// Spans are appended when they end; show them in start order
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Timings:")
+	for _, span := range spans {
+		fmt.Fprintf(os.Stderr, "  %-32s %10s\n", span.Name, formatElapsed(span.Duration))
+	}
+	fmt.Fprintf(os.Stderr, "  %-32s %10s\n", "total", formatElapsed(time.Since(commandStart)))
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// toolDocServer is one server section of the generated tool reference
+type toolDocServer struct {
+	Name        string
+	Description string
+	Category    string
+	Framework   string
+	Endpoint    string
+	Status      string
+	Tools       []MCPTool
+}
+
+// toolDoc is the data passed to the tool reference template
+type toolDoc struct {
+	Source    string
+	Generated string
+	ToolCount int
+	Servers   []toolDocServer
+}
+
+// defaultToolDocTemplate renders toolDoc as a markdown reference grouped by server
+const defaultToolDocTemplate = `# MCP Tool Reference
+
+{{ .ToolCount }} tools on {{ len .Servers }} servers, generated from ` + "`{{ .Source }}`" + `.
+{{ range .Servers }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{ if .Category }}- **Category:** {{ .Category }}
+{{ end }}{{ if .Framework }}- **Framework:** {{ .Framework }}
+{{ end }}{{ if .Endpoint }}- **Endpoint:** ` + "`{{ .Endpoint }}`" + `
+{{ end }}
+| Tool | Description |
+|------|-------------|
+{{ range .Tools }}| ` + "`{{ cell .Name }}`" + ` | {{ cell .Description }} |
+{{ end }}{{ end }}`
+
+// toolDocFuncs are available to tool reference templates
+var toolDocFuncs = template.FuncMap{
+	// cell flattens and escapes a value for a markdown table cell
+	"cell": func(value string) string {
+		return markdownCell(strings.Join(strings.Fields(value), " "))
+	},
+}
+
+// buildToolDoc groups the registry's tools by server, in registry order. Tools
+// named on a server without a tool record are included without a description,
+// and tools of unknown servers get a section of their own at the end.
+func buildToolDoc(registry *MCPRegistry) toolDoc {
+	byServer := make(map[string][]MCPTool)
+	for _, tool := range registry.Tools {
+		byServer[tool.ServerName] = append(byServer[tool.ServerName], tool)
+	}
+
+	doc := toolDoc{Source: configFile, Generated: time.Now().Format(time.RFC3339)}
+	known := make(map[string]bool)
+	for _, server := range registry.Servers {
+		known[server.Name] = true
+		tools := byServer[server.Name]
+		described := make(map[string]bool, len(tools))
+		for _, tool := range tools {
+			described[tool.Name] = true
+		}
+		for _, name := range server.Tools {
+			if !described[name] {
+				tools = append(tools, MCPTool{Name: name, ServerName: server.Name})
+				described[name] = true
+			}
+		}
+		if len(tools) == 0 {
+			continue
+		}
+		doc.Servers = append(doc.Servers, toolDocServer{
+			Name:        server.Name,
+			Description: server.Description,
+			Category:    server.Metadata.Category,
+			Framework:   server.Metadata.Framework,
+			Endpoint:    server.Endpoint,
+			Status:      server.Status,
+			Tools:       tools,
+		})
+	}
+
+	var orphans []string
+	for name := range byServer {
+		if !known[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		section := name
+		if section == "" {
+			section = "Unassigned"
+		}
+		doc.Servers = append(doc.Servers, toolDocServer{Name: section, Tools: byServer[name]})
+	}
+
+	for i := range doc.Servers {
+		tools := doc.Servers[i].Tools
+		sort.SliceStable(tools, func(a, b int) bool { return tools[a].Name < tools[b].Name })
+		doc.ToolCount += len(tools)
+	}
+	return doc
+}
+
+// exportToolDocs writes a tool reference for the local registry. templateText
+// replaces the built-in markdown layout; a value starting with @ names a file.
+func exportToolDocs(format, templateText, output string) error {
+	if format != "markdown" {
+		return usageError("invalid --export value %q (expected markdown)", format)
+	}
+
+	if path, ok := strings.CutPrefix(templateText, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return usageError("failed to read --template file: %v", err)
+		}
+		templateText = string(data)
+	}
+	if templateText == "" {
+		templateText = defaultToolDocTemplate
+	}
+	tmpl, err := template.New("tools").Funcs(toolDocFuncs).Option("missingkey=error").Parse(templateText)
+	if err != nil {
+		return usageError("invalid --template: %v", err)
+	}
+
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+	doc := buildToolDoc(registry)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, doc); err != nil {
+		return usageError("invalid --template: %v", err)
+	}
+
+	if output == "" || output == "-" {
+		printResult("%s", buf.String())
+		return nil
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", output, err)
+	}
+	printProgress("📝 Wrote %d tools on %d servers to %s\n", doc.ToolCount, len(doc.Servers), output)
+	return nil
+}
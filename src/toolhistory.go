@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxHistoryResult caps how much of a tool result is kept in the history file
+const maxHistoryResult = 2000
+
+// toolCallRecord is one line of the tool call history
+type toolCallRecord struct {
+	ID         int                    `json:"id"`
+	Timestamp  string                 `json:"timestamp"`
+	Server     string                 `json:"server"`
+	Tool       string                 `json:"tool"`
+	Args       map[string]interface{} `json:"args"`
+	Result     string                 `json:"result,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+var toolHistoryMu sync.Mutex
+
+// toolHistoryPath returns where tool calls are recorded
+func toolHistoryPath() string {
+	return filepath.Join(devgenDir(), "tool_history.jsonl")
+}
+
+// maskArgs returns a copy of tool arguments with secret keys and values masked,
+// descending into nested objects and arrays
+func maskArgs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSecretKey(key) {
+				masked[key] = secretMask
+				continue
+			}
+			masked[key] = maskArgs(item)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = maskArgs(item)
+		}
+		return masked
+	case string:
+		return maskSecrets(v)
+	}
+	return value
+}
+
+// argsWereMasked reports whether masking changed anything, i.e. a replay would
+// not send the original arguments
+func argsWereMasked(args map[string]interface{}) bool {
+	data, _ := json.Marshal(args)
+	return strings.Contains(string(data), secretMask)
+}
+
+// readToolHistory returns every recorded call, oldest first. A missing file is empty history.
+func readToolHistory() ([]toolCallRecord, error) {
+	file, err := os.Open(toolHistoryPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tool history: %v", err)
+	}
+	defer file.Close()
+
+	var records []toolCallRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record toolCallRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tool history: %v", err)
+	}
+	return records, nil
+}
+
+// recordToolCall appends a call to the history with secrets masked and the
+// result truncated
+func recordToolCall(serverName, toolName string, args map[string]interface{}, raw json.RawMessage, duration time.Duration, callErr error) error {
+	toolHistoryMu.Lock()
+	defer toolHistoryMu.Unlock()
+
+	records, err := readToolHistory()
+	if err != nil {
+		return err
+	}
+	id := 1
+	if len(records) > 0 {
+		id = records[len(records)-1].ID + 1
+	}
+
+	masked, _ := maskArgs(args).(map[string]interface{})
+	record := toolCallRecord{
+		ID:         id,
+		Timestamp:  time.Now().Format(time.RFC3339),
+		Server:     serverName,
+		Tool:       toolName,
+		Args:       masked,
+		DurationMs: duration.Milliseconds(),
+		Success:    callErr == nil,
+	}
+	if raw != nil {
+		result := maskSecrets(string(raw))
+		if len(result) > maxHistoryResult {
+			result = result[:maxHistoryResult] + "…"
+		}
+		record.Result = result
+	}
+	if callErr != nil {
+		record.Error = maskSecrets(callErr.Error())
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool history: %v", err)
+	}
+
+	path := toolHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create tool history directory: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open tool history: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write tool history: %v", err)
+	}
+	return nil
+}
+
+// showToolHistory lists recorded calls, most recent last
+func showToolHistory(servers []string, limit int, asJSON bool) error {
+	records, err := readToolHistory()
+	if err != nil {
+		return err
+	}
+
+	var matched []toolCallRecord
+	for _, record := range records {
+		if matchesAny(record.Server, servers) {
+			matched = append(matched, record)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+
+	if asJSON {
+		if matched == nil {
+			matched = []toolCallRecord{}
+		}
+		data, err := json.MarshalIndent(matched, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	if len(matched) == 0 {
+		printResult("No tool calls recorded yet (%s)\n", toolHistoryPath())
+		return nil
+	}
+
+	headers := []string{"ID", "TIME", "SERVER", "TOOL", "DURATION", "RESULT", "ARGS"}
+	cells := make([][]string, 0, len(matched))
+	for _, record := range matched {
+		status := "ok"
+		if !record.Success {
+			status = "error"
+		}
+		args, _ := json.Marshal(record.Args)
+		cells = append(cells, []string{
+			strconv.Itoa(record.ID),
+			record.Timestamp,
+			record.Server,
+			record.Tool,
+			formatElapsed(time.Duration(record.DurationMs) * time.Millisecond),
+			status,
+			truncateText(string(args), 60),
+		})
+	}
+
+	if !isTerminal(os.Stdout) {
+		printResult("%s", renderTSV(headers, cells))
+		return nil
+	}
+
+	printProgress("🕘 Tool call history (%d calls)\n\n", len(matched))
+	printResult("%s\n", renderTable(headers, cells, -1))
+	return nil
+}
+
+// truncateText shortens text to at most width runes, marking the cut with an ellipsis
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// replayToolCall re-runs a recorded call. overrideArgs, when set, replaces the
+// stored arguments, which is required when they were masked.
+func replayToolCall(id int, overrideArgs string, timeout time.Duration, asJSON bool) error {
+	records, err := readToolHistory()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.ID != id {
+			continue
+		}
+		rawArgs := overrideArgs
+		if rawArgs == "" {
+			if argsWereMasked(record.Args) {
+				return validationError("call %d had secret arguments that were masked in the history; pass them again with --args", id)
+			}
+			data, err := json.Marshal(record.Args)
+			if err != nil {
+				return err
+			}
+			rawArgs = string(data)
+		}
+		printProgress("🔁 Replaying call %d: %s/%s\n", id, record.Server, record.Tool)
+		return invokeTool(record.Server, record.Tool, rawArgs, timeout, asJSON)
+	}
+	return notFoundError("no tool call with id %d in %s", id, toolHistoryPath())
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minUsesForErrorRatio is how many calls a tool needs before its error ratio
+// is judged, so a single failed call does not flag a tool
+const minUsesForErrorRatio = 5
+
+// toolUsageOptions select the tools reported by registry tools --unused
+type toolUsageOptions struct {
+	since      time.Duration
+	errorRatio float64
+	servers    []string
+	asJSON     bool
+}
+
+// toolUsageFinding is a tool reported as unused or failing
+type toolUsageFinding struct {
+	Server     string   `json:"server"`
+	Tool       string   `json:"tool"`
+	UseCount   int      `json:"use_count"`
+	ErrorCount int      `json:"error_count"`
+	ErrorRatio float64  `json:"error_ratio"`
+	LastUsed   string   `json:"last_used,omitempty"`
+	Reasons    []string `json:"reasons"`
+}
+
+// toolUsageReasons explains why a tool is a removal or repair candidate: never
+// used, not used since cutoff (when since is set), or failing at least
+// errorRatio of its calls. It returns nil for a tool in good standing.
+func toolUsageReasons(tool MCPTool, cutoff time.Time, since time.Duration, errorRatio float64) []string {
+	var reasons []string
+	if tool.UseCount == 0 || tool.LastUsed == "" {
+		reasons = append(reasons, "never used")
+	} else if since > 0 {
+		if lastUsed, ok := parseRegistryTime(tool.LastUsed); !ok || lastUsed.Before(cutoff) {
+			reasons = append(reasons, "not used in "+formatAge(since))
+		}
+	}
+	if errorRatio > 0 && tool.UseCount >= minUsesForErrorRatio && float64(tool.ErrorCount)/float64(tool.UseCount) >= errorRatio {
+		reasons = append(reasons, fmt.Sprintf("%d of %d calls failed", tool.ErrorCount, tool.UseCount))
+	}
+	return reasons
+}
+
+// formatAge renders a --since threshold the way it is usually written, e.g. 30d or 12h0m0s
+func formatAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	return d.String()
+}
+
+// listUnusedTools reports tools in the local registry's tool index that have
+// never been used, have not been used within opts.since, or fail too often.
+// Usage is only tracked for tools in the index, so tools a server lists
+// without an index entry are not reported.
+func listUnusedTools(opts toolUsageOptions) error {
+	registry, err := loadMCPRegistry()
+	if err != nil {
+		return registryLoadError(err)
+	}
+
+	cutoff := time.Now().Add(-opts.since)
+	findings := []toolUsageFinding{}
+	for _, tool := range registry.Tools {
+		if !matchesAny(tool.ServerName, opts.servers) {
+			continue
+		}
+		reasons := toolUsageReasons(tool, cutoff, opts.since, opts.errorRatio)
+		if len(reasons) == 0 {
+			continue
+		}
+		finding := toolUsageFinding{
+			Server:     tool.ServerName,
+			Tool:       tool.Name,
+			UseCount:   tool.UseCount,
+			ErrorCount: tool.ErrorCount,
+			LastUsed:   tool.LastUsed,
+			Reasons:    reasons,
+		}
+		if tool.UseCount > 0 {
+			finding.ErrorRatio = float64(tool.ErrorCount) / float64(tool.UseCount)
+		}
+		findings = append(findings, finding)
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"tracked": len(registry.Tools),
+			"count":   len(findings),
+			"tools":   findings,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		printJSON(data)
+		return nil
+	}
+
+	if len(findings) == 0 {
+		printResult("✅ All %d tracked tools are in use\n", len(registry.Tools))
+		return nil
+	}
+
+	printProgress("🧹 %d of %d tracked tools are unused or failing\n\n", len(findings), len(registry.Tools))
+	headers := []string{"SERVER", "TOOL", "USES", "ERRORS", "LAST USED", "REASON"}
+	cells := make([][]string, 0, len(findings))
+	for _, finding := range findings {
+		lastUsed := finding.LastUsed
+		if lastUsed == "" {
+			lastUsed = "never"
+		}
+		cells = append(cells, []string{finding.Server, finding.Tool, strconv.Itoa(finding.UseCount), strconv.Itoa(finding.ErrorCount), lastUsed, strings.Join(finding.Reasons, "; ")})
+	}
+	if isTerminal(os.Stdout) {
+		printResult("%s\n", renderTable(headers, cells, -1))
+	} else {
+		printResult("%s", renderTSV(headers, cells))
+	}
+	return nil
+}